@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"github.com/saleh-ghazimoradi/Projectopher/infra/mongodb"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"log/slog"
+	"os"
+)
+
+func newLogger() *slog.Logger {
+	return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				t := a.Value.Time()
+				a.Value = slog.StringValue(t.Format("2006-01-02T15:04:05"))
+			}
+			return a
+		},
+	}))
+}
+
+func connectMongo(cfg *config.Config) (*mongo.Client, *mongo.Database, error) {
+	mongo := mongodb.NewMongoDB(
+		mongodb.WithHost(cfg.MongoDB.Host),
+		mongodb.WithPort(cfg.MongoDB.Port),
+		mongodb.WithUser(cfg.MongoDB.User),
+		mongodb.WithPass(cfg.MongoDB.Pass),
+		mongodb.WithDBName(cfg.MongoDB.DBName),
+		mongodb.WithAuthSource(cfg.MongoDB.AuthSource),
+		mongodb.WithMaxPoolSize(cfg.MongoDB.MaxPoolSize),
+		mongodb.WithMinPoolSize(cfg.MongoDB.MinPoolSize),
+		mongodb.WithTimeout(cfg.MongoDB.Timeout),
+	)
+
+	return mongo.Connect()
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// keysCmd represents the keys command
+var keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage JWT signing keys",
+}
+
+var keysRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Generate a new JWT signing key",
+	Long: `Generate a new private key in JWT_KEYS_DIR. The newest key signs tokens once
+the server restarts, while older keys keep verifying tokens issued before the
+rotation. With several instances, pin JWT_SIGNING_KEY_ID to the previous kid
+until every instance has loaded the new key, then unset it.
+
+--prune removes keys superseded longer than JWT_REFRESH_TOKEN_EXPIRES ago.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		algorithm, _ := cmd.Flags().GetString("alg")
+		prune, _ := cmd.Flags().GetBool("prune")
+
+		logger := newLogger()
+
+		cfg, err := config.GetInstance()
+		if err != nil {
+			logger.Error("failed to get config", "error", err.Error())
+			os.Exit(1)
+		}
+
+		if algorithm == "" {
+			algorithm = cfg.JWT.Algorithm
+		}
+
+		kid, err := utils.GenerateKey(cfg.JWT.KeysDir, algorithm)
+		if err != nil {
+			logger.Error("failed to generate key", "error", err.Error())
+			os.Exit(1)
+		}
+		logger.Info("key generated", "kid", kid, "alg", algorithm, "dir", cfg.JWT.KeysDir)
+
+		if prune {
+			removed, err := utils.PruneKeys(cfg.JWT.KeysDir, time.Now().Add(-cfg.JWT.RefreshTokenExpires))
+			if err != nil {
+				logger.Error("failed to prune keys", "error", err.Error())
+				os.Exit(1)
+			}
+			logger.Info("keys pruned", "kids", removed)
+		}
+
+		fmt.Println(kid)
+	},
+}
+
+func init() {
+	keysRotateCmd.Flags().String("alg", "", "Key algorithm, RS256 or EdDSA (defaults to JWT_ALGORITHM)")
+	keysRotateCmd.Flags().Bool("prune", false, "Remove keys no unexpired token can have been signed with")
+	keysCmd.AddCommand(keysRotateCmd)
+	rootCmd.AddCommand(keysCmd)
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/migrations"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Manage database schema migrations",
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Apply all pending migrations",
+	Run: func(cmd *cobra.Command, args []string) {
+		runMigrator(func(ctx context.Context, logger *slog.Logger, migrator *migrations.Migrator) error {
+			count, err := migrator.Up(ctx)
+			if err != nil {
+				return err
+			}
+			logger.Info("migrations applied", "count", count)
+			return nil
+		})
+	},
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Revert the most recently applied migrations",
+	Run: func(cmd *cobra.Command, args []string) {
+		steps, _ := cmd.Flags().GetInt("steps")
+		runMigrator(func(ctx context.Context, logger *slog.Logger, migrator *migrations.Migrator) error {
+			count, err := migrator.Down(ctx, steps)
+			if err != nil {
+				return err
+			}
+			logger.Info("migrations reverted", "count", count)
+			return nil
+		})
+	},
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show applied and pending migrations",
+	Run: func(cmd *cobra.Command, args []string) {
+		runMigrator(func(ctx context.Context, logger *slog.Logger, migrator *migrations.Migrator) error {
+			statuses, err := migrator.Status(ctx)
+			if err != nil {
+				return err
+			}
+
+			for _, status := range statuses {
+				state := "pending"
+				if status.Applied {
+					state = "applied " + status.AppliedAt.Format("2006-01-02T15:04:05")
+				}
+				fmt.Printf("%04d  %-60s  %s\n", status.Version, status.Description, state)
+			}
+			return nil
+		})
+	},
+}
+
+func runMigrator(fn func(ctx context.Context, logger *slog.Logger, migrator *migrations.Migrator) error) {
+	logger := newLogger()
+
+	cfg, err := config.GetInstance()
+	if err != nil {
+		logger.Error("failed to get config", "error", err.Error())
+		os.Exit(1)
+	}
+
+	client, database, err := connectMongo(cfg)
+	if err != nil {
+		logger.Error("failed to connect", "error", err.Error())
+		os.Exit(1)
+	}
+
+	defer func() {
+		if err := client.Disconnect(context.Background()); err != nil {
+			logger.Error("failed to disconnect", "error", err.Error())
+		}
+	}()
+
+	migrator := migrations.NewMigrator(database, "schema_migrations", logger, migrations.All())
+	if err := fn(context.Background(), logger, migrator); err != nil {
+		logger.Error("migration failed", "error", err.Error())
+		client.Disconnect(context.Background())
+		os.Exit(1)
+	}
+}
+
+func init() {
+	migrateDownCmd.Flags().Int("steps", 1, "Number of migrations to revert")
+	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateStatusCmd)
+	rootCmd.AddCommand(migrateCmd)
+}
@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"github.com/saleh-ghazimoradi/Projectopher/config"
 	"github.com/saleh-ghazimoradi/Projectopher/infra/AI"
-	"github.com/saleh-ghazimoradi/Projectopher/infra/mongodb"
+	"github.com/saleh-ghazimoradi/Projectopher/infra/mailer"
+	"github.com/saleh-ghazimoradi/Projectopher/infra/oidc"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/authz"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/handlers"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/middlewares"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/routes"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/server"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/service"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
 	"github.com/tmc/langchaingo/llms/openai"
 	"log/slog"
 	"os"
@@ -26,15 +29,7 @@ var runCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("run called")
 
-		logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
-				if len(groups) == 0 && a.Key == slog.TimeKey {
-					t := a.Value.Time()
-					a.Value = slog.StringValue(t.Format("2006-01-02T15:04:05"))
-				}
-				return a
-			},
-		}))
+		logger := newLogger()
 
 		cfg, err := config.GetInstance()
 		if err != nil {
@@ -42,19 +37,7 @@ var runCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		mongo := mongodb.NewMongoDB(
-			mongodb.WithHost(cfg.MongoDB.Host),
-			mongodb.WithPort(cfg.MongoDB.Port),
-			mongodb.WithUser(cfg.MongoDB.User),
-			mongodb.WithPass(cfg.MongoDB.Pass),
-			mongodb.WithDBName(cfg.MongoDB.DBName),
-			mongodb.WithAuthSource(cfg.MongoDB.AuthSource),
-			mongodb.WithMaxPoolSize(cfg.MongoDB.MaxPoolSize),
-			mongodb.WithMinPoolSize(cfg.MongoDB.MinPoolSize),
-			mongodb.WithTimeout(cfg.MongoDB.Timeout),
-		)
-
-		client, mongodb, err := mongo.Connect()
+		client, mongodb, err := connectMongo(cfg)
 		if err != nil {
 			logger.Error("failed to connect", "error", err.Error())
 			os.Exit(1)
@@ -67,7 +50,6 @@ var runCmd = &cobra.Command{
 			}
 		}()
 
-		middleware := middlewares.NewMiddleware(cfg, logger)
 		llm, err := openai.New(openai.WithToken(cfg.OpenAI.ApiKey))
 		if err != nil {
 			logger.Error("failed to init openai", "error", err.Error())
@@ -76,31 +58,130 @@ var runCmd = &cobra.Command{
 
 		openAI := AI.NewOpenAI(cfg, llm)
 
+		mail, err := mailer.New(cfg, logger)
+		if err != nil {
+			logger.Error("failed to init mailer", "error", err.Error())
+			os.Exit(1)
+		}
+
+		passwordHasher, err := utils.NewPasswordHasher(cfg)
+		if err != nil {
+			logger.Error("failed to init password hasher", "error", err.Error())
+			os.Exit(1)
+		}
+
+		keys, err := utils.LoadKeySet(cfg)
+		if err != nil {
+			logger.Error("failed to load jwt keys", "error", err.Error())
+			os.Exit(1)
+		}
+
+		clientIPs, err := utils.NewClientIPResolver(cfg.Server.TrustedProxies)
+		if err != nil {
+			logger.Error("failed to parse trusted proxies", "error", err.Error())
+			os.Exit(1)
+		}
+
+		if cfg.Pagination.CursorSecret == "" {
+			logger.Warn("PAGINATION_CURSOR_SECRET is not set, cursors will not survive a restart")
+		}
+
+		cursors, err := utils.NewCursorSigner(cfg.Pagination.CursorSecret)
+		if err != nil {
+			logger.Error("failed to init cursor signer", "error", err.Error())
+			os.Exit(1)
+		}
+
 		movieRepository := repository.NewMovieRepository(mongodb, "movie")
 		genreRepository := repository.NewGenresRepository(mongodb, "genre")
 		rankRepository := repository.NewRankingsRepository(mongodb, "rank")
 		userRepository := repository.NewUsersRepository(mongodb, "user")
-		tokenRepository := repository.NewTokenRepository(mongodb, "token")
+		tokenRepository := repository.NewTokenRepository(mongodb, "token", "password_reset")
+		roleRepository := repository.NewRoleRepository(mongodb, "role")
+		loginAttemptRepository := repository.NewLoginAttemptRepository(mongodb, "login_attempt")
+		revokedTokenRepository := repository.NewRevokedTokenRepository(mongodb, "revoked_token")
+		apiKeyRepository := repository.NewAPIKeyRepository(mongodb, "api_key")
+		oidcStateRepository := repository.NewOIDCStateRepository(mongodb, "oidc_state")
+		reviewRepository := repository.NewReviewRepository(mongodb, "review", "movie")
+		watchlistRepository := repository.NewWatchlistRepository(mongodb, "watchlist", "movie")
+		watchHistoryRepository := repository.NewWatchHistoryRepository(mongodb, "watch_history", "movie")
+		movieListRepository := repository.NewMovieListRepository(mongodb, "movie_list")
+
+		oidcProviders := oidc.New(cfg, nil)
+
+		permissionCache := authz.NewPermissionCache(roleRepository, cfg.Authz.PermissionCacheTTL)
+
+		denylist := authz.NewDenylist(revokedTokenRepository, logger)
+		if err := denylist.Sync(context.Background()); err != nil {
+			logger.Error("failed to load token denylist", "error", err.Error())
+			os.Exit(1)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go denylist.Run(ctx, cfg.Authz.DenylistSyncInterval)
+
+		apiKeyService := service.NewAPIKeyService(cfg, apiKeyRepository, userRepository, permissionCache, logger)
+
+		middleware := middlewares.NewMiddleware(cfg, logger, denylist, permissionCache, keys, apiKeyService, clientIPs)
 
-		movieService := service.NewMovieService(movieRepository, rankRepository, genreRepository, userRepository, openAI, cfg)
-		authService := service.NewAuthService(cfg, userRepository, tokenRepository)
-		userService := service.NewUserService(userRepository)
+		movieService := service.NewMovieService(movieRepository, rankRepository, genreRepository, userRepository, reviewRepository, watchlistRepository, watchHistoryRepository, movieListRepository, openAI, cursors, cfg)
+		authService := service.NewAuthService(cfg, keys, userRepository, tokenRepository, loginAttemptRepository, apiKeyRepository, oidcStateRepository, oidcProviders, permissionCache, denylist, mail, passwordHasher, logger)
+		userService := service.NewUserService(userRepository, roleRepository, tokenRepository, loginAttemptRepository, apiKeyRepository, reviewRepository, watchlistRepository, watchHistoryRepository, movieListRepository, passwordHasher, cursors, denylist, logger)
+		genreService := service.NewGenreService(genreRepository, movieRepository, userRepository)
+		rankingService := service.NewRankingService(rankRepository, movieRepository)
+		roleService := service.NewRoleService(roleRepository, permissionCache)
+		mfaService := service.NewMFAService(cfg, userRepository)
+		sessionService := service.NewSessionService(userRepository, tokenRepository, denylist, logger)
+		reviewService := service.NewReviewService(reviewRepository, movieRepository, cursors, logger)
+		watchlistService := service.NewWatchlistService(watchlistRepository, watchHistoryRepository, movieRepository, cursors, logger)
+		movieListService := service.NewMovieListService(movieListRepository, movieRepository, cursors, logger)
 
 		healthHandler := handlers.NewHealthHandler(cfg)
 		movieHandler := handlers.NewMovieHandler(movieService)
 		authHandler := handlers.NewAuthHandler(authService)
 		userHandler := handlers.NewUserHandler(userService)
+		genreHandler := handlers.NewGenreHandler(genreService)
+		rankingHandler := handlers.NewRankingHandler(rankingService)
+		roleHandler := handlers.NewRoleHandler(roleService)
+		mfaHandler := handlers.NewMFAHandler(mfaService)
+		sessionHandler := handlers.NewSessionHandler(sessionService)
+		jwksHandler := handlers.NewJWKSHandler(keys)
+		apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+		reviewHandler := handlers.NewReviewHandler(reviewService)
+		watchlistHandler := handlers.NewWatchlistHandler(watchlistService)
+		movieListHandler := handlers.NewMovieListHandler(movieListService)
 
 		healthRoute := routes.NewHealthRoute(healthHandler)
-		movieRoute := routes.NewMovieRoute(middleware, movieHandler)
+		movieRoute := routes.NewMovieRoute(movieHandler)
 		authRoute := routes.NewAuthRoute(authHandler)
-		userRoute := routes.NewUserRoute(middleware, userHandler)
+		userRoute := routes.NewUserRoute(userHandler)
+		genreRoute := routes.NewGenreRoute(genreHandler)
+		rankingRoute := routes.NewRankingRoute(rankingHandler)
+		roleRoute := routes.NewRoleRoute(roleHandler)
+		mfaRoute := routes.NewMFARoute(mfaHandler)
+		sessionRoute := routes.NewSessionRoute(sessionHandler)
+		jwksRoute := routes.NewJWKSRoute(jwksHandler)
+		apiKeyRoute := routes.NewAPIKeyRoute(apiKeyHandler)
+		reviewRoute := routes.NewReviewRoute(reviewHandler)
+		watchlistRoute := routes.NewWatchlistRoute(watchlistHandler)
+		movieListRoute := routes.NewMovieListRoute(movieListHandler)
 
 		register := routes.NewRegister(
 			routes.WithHealthRoute(healthRoute),
 			routes.WithAuthRoute(authRoute),
 			routes.WithMovieRoute(movieRoute),
 			routes.WithUserRoute(userRoute),
+			routes.WithGenreRoute(genreRoute),
+			routes.WithRankingRoute(rankingRoute),
+			routes.WithRoleRoute(roleRoute),
+			routes.WithMFARoute(mfaRoute),
+			routes.WithSessionRoute(sessionRoute),
+			routes.WithJWKSRoute(jwksRoute),
+			routes.WithAPIKeyRoute(apiKeyRoute),
+			routes.WithReviewRoute(reviewRoute),
+			routes.WithWatchlistRoute(watchlistRoute),
+			routes.WithMovieListRoute(movieListRoute),
 			routes.WithMiddleware(middleware),
 		)
 
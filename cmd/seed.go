@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/seed"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// seedCmd represents the seed command
+var seedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Load genres, rankings, movies and users from fixture files",
+	Long: `Load fixtures from JSON or YAML files. Genres and rankings are upserted by
+their id/value, movies and users are only inserted when no record with the same
+imdb_id/email exists, so the command is safe to run on every bootstrap.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		files, _ := cmd.Flags().GetStringSlice("file")
+
+		logger := newLogger()
+
+		cfg, err := config.GetInstance()
+		if err != nil {
+			logger.Error("failed to get config", "error", err.Error())
+			os.Exit(1)
+		}
+
+		passwordHasher, err := utils.NewPasswordHasher(cfg)
+		if err != nil {
+			logger.Error("failed to init password hasher", "error", err.Error())
+			os.Exit(1)
+		}
+
+		fixtures, err := seed.LoadFiles(files...)
+		if err != nil {
+			logger.Error("failed to load fixtures", "error", err.Error())
+			os.Exit(1)
+		}
+
+		client, mongodb, err := connectMongo(cfg)
+		if err != nil {
+			logger.Error("failed to connect", "error", err.Error())
+			os.Exit(1)
+		}
+
+		defer func() {
+			if err := client.Disconnect(context.Background()); err != nil {
+				logger.Error("failed to disconnect", "error", err.Error())
+			}
+		}()
+
+		seeder := seed.NewSeeder(
+			repository.NewGenresRepository(mongodb, "genre"),
+			repository.NewRankingsRepository(mongodb, "rank"),
+			repository.NewMovieRepository(mongodb, "movie"),
+			repository.NewUsersRepository(mongodb, "user"),
+			repository.NewRoleRepository(mongodb, "role"),
+			passwordHasher,
+			logger,
+		)
+
+		if err := seeder.Seed(context.Background(), fixtures); err != nil {
+			logger.Error("failed to seed", "error", err.Error())
+			client.Disconnect(context.Background())
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	seedCmd.Flags().StringSliceP("file", "f", []string{"fixtures/base.yaml"}, "Fixture files to load (.json, .yaml or .yml)")
+	rootCmd.AddCommand(seedCmd)
+}
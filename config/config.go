@@ -2,6 +2,7 @@ package config
 
 import (
 	"github.com/caarlos0/env/v11"
+	"strings"
 	"sync"
 	"time"
 )
@@ -19,6 +20,85 @@ type Config struct {
 	RateLimiter RateLimiter
 	JWT         JWT
 	OpenAI      OpenAI
+	Authz       Authz
+	Auth        Auth
+	Mailer      Mailer
+	Password    Password
+	Lockout     Lockout
+	MFA         MFA
+	OIDC        OIDC
+	Pagination  Pagination
+}
+
+type Pagination struct {
+	// CursorSecret signs cursor tokens. When empty a random secret is generated
+	// at startup, so cursors break on restart and across instances.
+	CursorSecret string `env:"PAGINATION_CURSOR_SECRET"`
+}
+
+// OIDC lists the enabled login providers by name. Each one is configured
+// through its own OIDC_<NAME>_* variables, e.g. OIDC_GOOGLE_ISSUER.
+type OIDC struct {
+	Providers    []string      `env:"OIDC_PROVIDERS" envSeparator:","`
+	StateExpires time.Duration `env:"OIDC_STATE_EXPIRES" envDefault:"10m"`
+	Provider     map[string]OIDCProvider
+}
+
+type OIDCProvider struct {
+	Issuer       string   `env:"ISSUER"`
+	ClientId     string   `env:"CLIENT_ID"`
+	ClientSecret string   `env:"CLIENT_SECRET"`
+	RedirectURL  string   `env:"REDIRECT_URL"`
+	Scopes       []string `env:"SCOPES" envSeparator:"," envDefault:"openid,email,profile"`
+}
+
+type MFA struct {
+	Issuer           string        `env:"MFA_ISSUER" envDefault:"Projectopher"`
+	ChallengeExpires time.Duration `env:"MFA_CHALLENGE_EXPIRES" envDefault:"5m"`
+	RecoveryCodes    int           `env:"MFA_RECOVERY_CODES" envDefault:"10"`
+	EnforceAdmin     bool          `env:"MFA_ENFORCE_ADMIN"`
+}
+
+type Lockout struct {
+	MaxAttempts      int           `env:"LOCKOUT_MAX_ATTEMPTS" envDefault:"10"`
+	MaxAttemptsPerIP int           `env:"LOCKOUT_MAX_ATTEMPTS_PER_IP" envDefault:"100"`
+	Duration         time.Duration `env:"LOCKOUT_DURATION" envDefault:"15m"`
+	BackoffBase      time.Duration `env:"LOCKOUT_BACKOFF_BASE" envDefault:"1s"`
+	BackoffMax       time.Duration `env:"LOCKOUT_BACKOFF_MAX" envDefault:"1m"`
+	Window           time.Duration `env:"LOCKOUT_WINDOW" envDefault:"1h"`
+}
+
+type Password struct {
+	// Algorithm is either bcrypt or argon2id.
+	Algorithm     string `env:"PASSWORD_ALGORITHM" envDefault:"argon2id"`
+	BcryptCost    int    `env:"PASSWORD_BCRYPT_COST" envDefault:"12"`
+	Argon2Memory  uint32 `env:"PASSWORD_ARGON2_MEMORY" envDefault:"65536"`
+	Argon2Time    uint32 `env:"PASSWORD_ARGON2_TIME" envDefault:"3"`
+	Argon2Threads uint8  `env:"PASSWORD_ARGON2_THREADS" envDefault:"2"`
+}
+
+type Auth struct {
+	PasswordResetURL     string        `env:"AUTH_PASSWORD_RESET_URL"`
+	PasswordResetExpires time.Duration `env:"AUTH_PASSWORD_RESET_EXPIRES" envDefault:"30m"`
+	EmailVerificationURL string        `env:"AUTH_EMAIL_VERIFICATION_URL"`
+	EmailVerifyExpires   time.Duration `env:"AUTH_EMAIL_VERIFY_EXPIRES" envDefault:"24h"`
+	// UnverifiedLogin is one of allow, restricted or deny.
+	UnverifiedLogin string `env:"AUTH_UNVERIFIED_LOGIN" envDefault:"allow"`
+}
+
+type Mailer struct {
+	Driver   string `env:"MAILER_DRIVER" envDefault:"log"`
+	Host     string `env:"MAILER_HOST"`
+	Port     string `env:"MAILER_PORT"`
+	Username string `env:"MAILER_USERNAME"`
+	Password string `env:"MAILER_PASSWORD"`
+	From     string `env:"MAILER_FROM"`
+	Dir      string `env:"MAILER_DIR" envDefault:"tmp/mail"`
+}
+
+type Authz struct {
+	PermissionCacheTTL   time.Duration `env:"AUTHZ_PERMISSION_CACHE_TTL"`
+	DenylistSyncInterval time.Duration `env:"AUTHZ_DENYLIST_SYNC_INTERVAL" envDefault:"5s"`
 }
 
 type OpenAI struct {
@@ -36,14 +116,22 @@ type JWT struct {
 	Secret              string        `env:"JWT_SECRET"`
 	ExpiresIn           time.Duration `env:"JWT_EXPIRES_IN"`
 	RefreshTokenExpires time.Duration `env:"JWT_REFRESH_TOKEN_EXPIRES"`
+	// Algorithm is one of HS256, RS256 or EdDSA. The asymmetric ones read PEM
+	// keys from KeysDir, named <kid>.pem.
+	Algorithm    string `env:"JWT_ALGORITHM" envDefault:"HS256"`
+	KeysDir      string `env:"JWT_KEYS_DIR" envDefault:"keys"`
+	SigningKeyId string `env:"JWT_SIGNING_KEY_ID"`
+	Issuer       string `env:"JWT_ISSUER" envDefault:"projectopher"`
+	Audience     string `env:"JWT_AUDIENCE" envDefault:"projectopher-api"`
 }
 
 type Server struct {
-	Host         string        `env:"SERVER_HOST"`
-	Port         string        `env:"SERVER_PORT"`
-	IdleTimeout  time.Duration `env:"SERVER_IDLE_TIMEOUT"`
-	ReadTimeout  time.Duration `env:"SERVER_READ_TIMEOUT"`
-	WriteTimeout time.Duration `env:"SERVER_WRITE_TIMEOUT"`
+	Host           string        `env:"SERVER_HOST"`
+	Port           string        `env:"SERVER_PORT"`
+	IdleTimeout    time.Duration `env:"SERVER_IDLE_TIMEOUT"`
+	ReadTimeout    time.Duration `env:"SERVER_READ_TIMEOUT"`
+	WriteTimeout   time.Duration `env:"SERVER_WRITE_TIMEOUT"`
+	TrustedProxies []string      `env:"SERVER_TRUSTED_PROXIES" envSeparator:","`
 }
 
 type MongoDB struct {
@@ -71,6 +159,16 @@ func GetInstance() (*Config, error) {
 		if initErr != nil {
 			initErr = nil
 		}
+
+		instance.OIDC.Provider = make(map[string]OIDCProvider, len(instance.OIDC.Providers))
+		for _, name := range instance.OIDC.Providers {
+			var provider OIDCProvider
+			if err := env.ParseWithOptions(&provider, env.Options{Prefix: "OIDC_" + strings.ToUpper(name) + "_"}); err != nil {
+				initErr = err
+				return
+			}
+			instance.OIDC.Provider[name] = provider
+		}
 	})
 	return instance, initErr
 }
@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type logMailer struct {
+	logger *slog.Logger
+}
+
+func (l *logMailer) Send(ctx context.Context, message *Message) error {
+	l.logger.InfoContext(ctx, "mail sent", "to", message.To, "subject", message.Subject, "body", message.Body)
+	return nil
+}
+
+func NewLogMailer(logger *slog.Logger) Mailer {
+	return &logMailer{
+		logger: logger,
+	}
+}
+
+type fileMailer struct {
+	config *config.Config
+}
+
+func (f *fileMailer) Send(ctx context.Context, message *Message) error {
+	if err := os.MkdirAll(f.config.Mailer.Dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), message.To)
+	content := fmt.Sprintf("From: %s\nTo: %s\nSubject: %s\n\n%s\n", f.config.Mailer.From, message.To, message.Subject, message.Body)
+
+	return os.WriteFile(filepath.Join(f.config.Mailer.Dir, name), []byte(content), 0o644)
+}
+
+func NewFileMailer(config *config.Config) Mailer {
+	return &fileMailer{
+		config: config,
+	}
+}
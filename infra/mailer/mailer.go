@@ -0,0 +1,31 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"log/slog"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+func New(config *config.Config, logger *slog.Logger) (Mailer, error) {
+	switch config.Mailer.Driver {
+	case "smtp":
+		return NewSMTPMailer(config), nil
+	case "file":
+		return NewFileMailer(config), nil
+	case "", "log":
+		return NewLogMailer(logger), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer driver %q", config.Mailer.Driver)
+	}
+}
@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+type smtpMailer struct {
+	config *config.Config
+}
+
+func (s *smtpMailer) Send(ctx context.Context, message *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.config.Mailer.Host, s.config.Mailer.Port)
+
+	var auth smtp.Auth
+	if s.config.Mailer.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Mailer.Username, s.config.Mailer.Password, s.config.Mailer.Host)
+	}
+
+	return smtp.SendMail(addr, auth, s.config.Mailer.From, []string{message.To}, s.compose(message))
+}
+
+func (s *smtpMailer) compose(message *Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.config.Mailer.From)
+	fmt.Fprintf(&b, "To: %s\r\n", message.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", message.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(message.Body)
+	return []byte(b.String())
+}
+
+func NewSMTPMailer(config *config.Config) Mailer {
+	return &smtpMailer{
+		config: config,
+	}
+}
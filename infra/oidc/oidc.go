@@ -0,0 +1,165 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"golang.org/x/oauth2"
+	"net/http"
+	"sync"
+)
+
+var (
+	ErrUnknownProvider = errors.New("unknown oidc provider")
+	ErrNonceMismatch   = errors.New("id token nonce does not match")
+)
+
+// Identity is what we take from a verified ID token.
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+	Name          string
+}
+
+// Provider runs the authorization-code flow against one issuer. Discovery
+// happens on first use, so the API starts even if a provider is unreachable.
+type Provider struct {
+	name   string
+	config config.OIDCProvider
+	client *http.Client
+
+	mu       sync.Mutex
+	oauth    *oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+func (p *Provider) Name() string {
+	return p.name
+}
+
+func (p *Provider) discover(ctx context.Context) (*oauth2.Config, *gooidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.oauth != nil {
+		return p.oauth, p.verifier, nil
+	}
+
+	// go-oidc keeps this context for later JWKS refreshes, so it must outlive
+	// the request that triggered discovery.
+	ctx = gooidc.ClientContext(context.WithoutCancel(ctx), p.client)
+	provider, err := gooidc.NewProvider(ctx, p.config.Issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("oidc discovery for %s: %w", p.name, err)
+	}
+
+	p.oauth = &oauth2.Config{
+		ClientID:     p.config.ClientId,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       p.config.Scopes,
+	}
+	p.verifier = provider.Verifier(&gooidc.Config{ClientID: p.config.ClientId})
+	return p.oauth, p.verifier, nil
+}
+
+// AuthCodeURL returns the URL to send the user to, bound to state, nonce and
+// the S256 challenge of verifier.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	oauth, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return oauth.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Exchange redeems code and returns the identity from the verified ID token.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Identity, error) {
+	oauth, idVerifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = gooidc.ClientContext(ctx, p.client)
+	token, err := oauth.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("oidc code exchange: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("oidc token response has no id_token")
+	}
+
+	idToken, err := idVerifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("oidc id token: %w", err)
+	}
+
+	if idToken.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified any    `json:"email_verified"`
+		GivenName     string `json:"given_name"`
+		FamilyName    string `json:"family_name"`
+		Name          string `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+
+	return &Identity{
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified == true || claims.EmailVerified == "true",
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+		Name:          claims.Name,
+	}, nil
+}
+
+type Providers struct {
+	providers map[string]*Provider
+}
+
+func (p *Providers) Get(name string) (*Provider, error) {
+	provider, ok := p.providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return provider, nil
+}
+
+// New builds the providers listed in config. client is used for discovery,
+// JWKS and token requests; nil means http.DefaultClient.
+func New(config *config.Config, client *http.Client) *Providers {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	providers := make(map[string]*Provider, len(config.OIDC.Provider))
+	for name, providerConfig := range config.OIDC.Provider {
+		providers[name] = &Provider{
+			name:   name,
+			config: providerConfig,
+			client: client,
+		}
+	}
+
+	return &Providers{providers: providers}
+}
+
+// GenerateVerifier returns a new PKCE code verifier.
+func GenerateVerifier() string {
+	return oauth2.GenerateVerifier()
+}
@@ -0,0 +1,318 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	stubClientId     = "projectopher"
+	stubClientSecret = "stub-secret"
+	stubKeyId        = "stub-key"
+)
+
+type stubAuthorization struct {
+	challenge string
+	nonce     string
+}
+
+// stubProvider is a minimal OIDC provider: discovery, JWKS and a token
+// endpoint that checks PKCE and issues an ID token for the code's nonce.
+type stubProvider struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims jwt.MapClaims
+
+	mu    sync.Mutex
+	codes map[string]stubAuthorization
+}
+
+func newStubProvider(t *testing.T) *stubProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stub := &stubProvider{
+		t:     t,
+		key:   key,
+		codes: make(map[string]stubAuthorization),
+		claims: jwt.MapClaims{
+			"sub":            "stub-subject",
+			"email":          "Jane@Example.com",
+			"email_verified": true,
+			"given_name":     "Jane",
+			"family_name":    "Doe",
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", stub.discovery)
+	mux.HandleFunc("GET /jwks", stub.jwks)
+	mux.HandleFunc("POST /token", stub.token)
+
+	// A TLS server only trusts clients built from server.Client(), which proves
+	// every request goes through the injected client.
+	stub.server = httptest.NewTLSServer(mux)
+	t.Cleanup(stub.server.Close)
+	return stub
+}
+
+func (s *stubProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeStubJSON(w, map[string]any{
+		"issuer":                                s.server.URL,
+		"authorization_endpoint":                s.server.URL + "/authorize",
+		"token_endpoint":                        s.server.URL + "/token",
+		"jwks_uri":                              s.server.URL + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (s *stubProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	writeStubJSON(w, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": stubKeyId,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (s *stubProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	authorization, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != authorization.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   s.server.URL,
+		"aud":   stubClientId,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": authorization.nonce,
+	}
+	for k, v := range s.claims {
+		claims[k] = v
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = stubKeyId
+	idToken, err := token.SignedString(s.key)
+	if err != nil {
+		s.t.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeStubJSON(w, map[string]any{
+		"access_token": "stub-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     idToken,
+	})
+}
+
+// authorize stands in for the user approving the login: it reads the
+// challenge and nonce from the authorization URL and returns a code.
+func (s *stubProvider) authorize(t *testing.T, authorizationURL string) string {
+	t.Helper()
+
+	parsed, err := url.Parse(authorizationURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := parsed.Query()
+	if got := query.Get("code_challenge_method"); got != "S256" {
+		t.Fatalf("code_challenge_method = %q, want S256", got)
+	}
+	if got := query.Get("client_id"); got != stubClientId {
+		t.Fatalf("client_id = %q, want %q", got, stubClientId)
+	}
+
+	code := "code-" + query.Get("state")
+	s.mu.Lock()
+	s.codes[code] = stubAuthorization{challenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	s.mu.Unlock()
+	return code
+}
+
+func writeStubJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newStubProviders(stub *stubProvider) *Providers {
+	cfg := &config.Config{OIDC: config.OIDC{Provider: map[string]config.OIDCProvider{
+		"stub": {
+			Issuer:       stub.server.URL,
+			ClientId:     stubClientId,
+			ClientSecret: stubClientSecret,
+			RedirectURL:  "https://app.example.com/callback",
+			Scopes:       []string{"openid", "email", "profile"},
+		},
+	}}}
+	return New(cfg, stub.server.Client())
+}
+
+func TestProviderExchange(t *testing.T) {
+	tests := []struct {
+		name          string
+		claims        jwt.MapClaims
+		nonce         string
+		verifier      func(verifier string) string
+		wantErr       error
+		wantAnyErr    bool
+		wantEmail     string
+		wantVerified  bool
+		wantGivenName string
+	}{
+		{
+			name:          "valid login",
+			wantEmail:     "Jane@Example.com",
+			wantVerified:  true,
+			wantGivenName: "Jane",
+		},
+		{
+			name:         "email_verified sent as a string",
+			claims:       jwt.MapClaims{"email_verified": "true"},
+			wantEmail:    "Jane@Example.com",
+			wantVerified: true,
+		},
+		{
+			name:      "unverified email",
+			claims:    jwt.MapClaims{"email_verified": false},
+			wantEmail: "Jane@Example.com",
+		},
+		{
+			name:    "nonce mismatch",
+			nonce:   "another-nonce",
+			wantErr: ErrNonceMismatch,
+		},
+		{
+			name:       "wrong pkce verifier",
+			verifier:   func(string) string { return GenerateVerifier() },
+			wantAnyErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newStubProvider(t)
+			for k, v := range tt.claims {
+				stub.claims[k] = v
+			}
+
+			provider, err := newStubProviders(stub).Get("stub")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.Background()
+			verifier := GenerateVerifier()
+			authorizationURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+			if err != nil {
+				t.Fatalf("AuthCodeURL: %v", err)
+			}
+			code := stub.authorize(t, authorizationURL)
+
+			nonce := "nonce-1"
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+			if tt.verifier != nil {
+				verifier = tt.verifier(verifier)
+			}
+
+			identity, err := provider.Exchange(ctx, code, verifier, nonce)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Exchange error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			case tt.wantAnyErr:
+				if err == nil {
+					t.Fatal("Exchange succeeded, want an error")
+				}
+				return
+			case err != nil:
+				t.Fatalf("Exchange: %v", err)
+			}
+
+			if identity.Subject != "stub-subject" {
+				t.Errorf("Subject = %q, want stub-subject", identity.Subject)
+			}
+			if identity.Email != tt.wantEmail {
+				t.Errorf("Email = %q, want %q", identity.Email, tt.wantEmail)
+			}
+			if identity.EmailVerified != tt.wantVerified {
+				t.Errorf("EmailVerified = %v, want %v", identity.EmailVerified, tt.wantVerified)
+			}
+			if tt.wantGivenName != "" && identity.GivenName != tt.wantGivenName {
+				t.Errorf("GivenName = %q, want %q", identity.GivenName, tt.wantGivenName)
+			}
+		})
+	}
+}
+
+func TestProviderRejectsTokenFromAnotherIssuer(t *testing.T) {
+	stub := newStubProvider(t)
+	stub.claims["iss"] = "https://evil.example.com"
+
+	provider, err := newStubProviders(stub).Get("stub")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	verifier := GenerateVerifier()
+	authorizationURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := provider.Exchange(ctx, stub.authorize(t, authorizationURL), verifier, "nonce-1"); err == nil {
+		t.Fatal("Exchange accepted an id token from another issuer")
+	}
+}
+
+func TestProvidersGetUnknown(t *testing.T) {
+	providers := New(&config.Config{}, nil)
+	if _, err := providers.Get("missing"); !errors.Is(err, ErrUnknownProvider) {
+		t.Fatalf("Get error = %v, want %v", err, ErrUnknownProvider)
+	}
+}
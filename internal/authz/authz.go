@@ -0,0 +1,151 @@
+package authz
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"slices"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionRead   Action = "read"
+	ActionList   Action = "list"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Subject is the authenticated caller. APIKey is set when the caller presented
+// an API key rather than an access token.
+type Subject struct {
+	UserId      string
+	Role        string
+	Permissions []string
+	APIKey      bool
+}
+
+func (s Subject) HasPermission(permission domain.Permission) bool {
+	return slices.Contains(s.Permissions, string(permission))
+}
+
+type Resource struct {
+	Kind    string
+	OwnerId string
+}
+
+type Policy func(subject Subject, action Action, resource Resource) bool
+
+var policies = map[string]Policy{
+	"user":       userPolicy,
+	"review":     reviewPolicy,
+	"movie_list": movieListPolicy,
+}
+
+func User(id string) Resource {
+	return Resource{Kind: "user", OwnerId: id}
+}
+
+func Users() Resource {
+	return Resource{Kind: "user"}
+}
+
+// Review is the review written by ownerId; Reviews stands for reviews in
+// general when creating one.
+func Review(ownerId string) Resource {
+	return Resource{Kind: "review", OwnerId: ownerId}
+}
+
+func Reviews() Resource {
+	return Resource{Kind: "review"}
+}
+
+// MovieList stands for the lists owned by ownerId.
+func MovieList(ownerId string) Resource {
+	return Resource{Kind: "movie_list", OwnerId: ownerId}
+}
+
+func SubjectFromCtx(ctx context.Context) (Subject, bool) {
+	userId, ok := utils.UserIdFromCtx(ctx)
+	if !ok || userId == "" {
+		return Subject{}, false
+	}
+
+	role, _ := utils.RoleFromCtx(ctx)
+	permissions, _ := utils.PermissionsFromCtx(ctx)
+	_, apiKey := utils.APIKeyIdFromCtx(ctx)
+	return Subject{UserId: userId, Role: role, Permissions: permissions, APIKey: apiKey}, true
+}
+
+// Can reports whether the authenticated caller stored in ctx may perform
+// action on resource. Unknown resource kinds are always denied.
+func Can(ctx context.Context, action Action, resource Resource) bool {
+	subject, ok := SubjectFromCtx(ctx)
+	if !ok {
+		return false
+	}
+
+	policy, ok := policies[resource.Kind]
+	if !ok {
+		return false
+	}
+
+	// An API key acts only through its scopes. Owning a resource lets a key
+	// read it, but changing anything needs a permission the key was granted.
+	if subject.APIKey && action != ActionRead && action != ActionList {
+		subject.UserId = ""
+	}
+
+	return policy(subject, action, resource)
+}
+
+func userPolicy(subject Subject, action Action, resource Resource) bool {
+	if subject.HasPermission(domain.PermissionUsersManage) {
+		return true
+	}
+
+	switch action {
+	case ActionRead, ActionUpdate, ActionDelete:
+		return resource.OwnerId != "" && resource.OwnerId == subject.UserId
+	default:
+		return false
+	}
+}
+
+// reviewPolicy lets anyone with a full account review movies and edit their own
+// reviews. Moderators may remove other people's reviews but not rewrite them.
+func reviewPolicy(subject Subject, action Action, resource Resource) bool {
+	if subject.Role == string(domain.UserRoleRestricted) {
+		return false
+	}
+
+	isOwner := resource.OwnerId != "" && resource.OwnerId == subject.UserId
+	switch action {
+	case ActionCreate:
+		return subject.UserId != ""
+	case ActionUpdate:
+		return isOwner
+	case ActionDelete:
+		return isOwner || subject.HasPermission(domain.PermissionContentModerate)
+	default:
+		return false
+	}
+}
+
+// movieListPolicy covers the lists themselves, not public viewing: anyone may
+// read a public list, and handlers check that before asking. Restricted users
+// keep their lists but cannot publish new ones or change them.
+func movieListPolicy(subject Subject, action Action, resource Resource) bool {
+	isOwner := resource.OwnerId != "" && resource.OwnerId == subject.UserId
+	switch action {
+	case ActionRead:
+		return isOwner || subject.HasPermission(domain.PermissionUsersManage)
+	case ActionCreate, ActionUpdate:
+		return isOwner && subject.Role != string(domain.UserRoleRestricted)
+	case ActionDelete:
+		return isOwner || subject.HasPermission(domain.PermissionContentModerate)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,119 @@
+package authz
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// syncOverlap re-reads a little history on every sync so revocations written
+// by instances with a slightly skewed clock are not missed.
+const syncOverlap = time.Minute
+
+type RevocationStore interface {
+	RevokeTokens(ctx context.Context, tokens []domain.RevokedToken) error
+	GetRevokedTokensSince(ctx context.Context, since time.Time) ([]domain.RevokedToken, error)
+}
+
+// Denylist keeps revoked access token ids in memory so Authenticate never hits
+// the database. Revocations are written through to the store and picked up by
+// other instances on their next Sync.
+type Denylist struct {
+	store    RevocationStore
+	logger   *slog.Logger
+	mu       sync.RWMutex
+	entries  map[string]time.Time
+	syncedAt time.Time
+}
+
+func (d *Denylist) Revoke(ctx context.Context, tokens ...domain.RevokedToken) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	if err := d.store.RevokeTokens(ctx, tokens); err != nil {
+		return err
+	}
+
+	d.add(tokens)
+	return nil
+}
+
+func (d *Denylist) IsRevoked(jti string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	expiresAt, ok := d.entries[jti]
+	return ok && time.Now().Before(expiresAt)
+}
+
+// Sync loads revocations recorded since the previous sync and drops entries
+// whose tokens have expired.
+func (d *Denylist) Sync(ctx context.Context) error {
+	d.mu.RLock()
+	since := d.syncedAt
+	d.mu.RUnlock()
+
+	startedAt := time.Now()
+	if !since.IsZero() {
+		since = since.Add(-syncOverlap)
+	}
+
+	tokens, err := d.store.GetRevokedTokensSince(ctx, since)
+	if err != nil {
+		return err
+	}
+
+	d.add(tokens)
+
+	d.mu.Lock()
+	for jti, expiresAt := range d.entries {
+		if startedAt.After(expiresAt) {
+			delete(d.entries, jti)
+		}
+	}
+	d.syncedAt = startedAt
+	d.mu.Unlock()
+
+	return nil
+}
+
+// Run syncs on every tick until ctx is cancelled.
+func (d *Denylist) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.Sync(ctx); err != nil {
+				d.logger.Error("failed to sync token denylist", "error", err.Error())
+			}
+		}
+	}
+}
+
+func (d *Denylist) add(tokens []domain.RevokedToken) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, token := range tokens {
+		d.entries[token.Jti] = token.ExpiresAt
+	}
+}
+
+func NewDenylist(store RevocationStore, logger *slog.Logger) *Denylist {
+	return &Denylist{
+		store:   store,
+		logger:  logger,
+		entries: make(map[string]time.Time),
+	}
+}
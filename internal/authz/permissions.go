@@ -0,0 +1,75 @@
+package authz
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"sync"
+	"time"
+)
+
+type RoleStore interface {
+	GetRoles(ctx context.Context) ([]domain.Role, error)
+}
+
+// PermissionCache keeps the role to permission mapping in memory and reloads
+// it from the store once the ttl has elapsed or after Invalidate is called.
+type PermissionCache struct {
+	store    RoleStore
+	ttl      time.Duration
+	mu       sync.RWMutex
+	roles    map[domain.UserRole][]domain.Permission
+	loadedAt time.Time
+}
+
+func (p *PermissionCache) Permissions(ctx context.Context, role domain.UserRole) ([]domain.Permission, error) {
+	p.mu.RLock()
+	if p.roles != nil && time.Since(p.loadedAt) < p.ttl {
+		permissions := p.roles[role]
+		p.mu.RUnlock()
+		return permissions, nil
+	}
+	p.mu.RUnlock()
+
+	if err := p.reload(ctx); err != nil {
+		return nil, err
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.roles[role], nil
+}
+
+func (p *PermissionCache) Invalidate() {
+	p.mu.Lock()
+	p.roles = nil
+	p.mu.Unlock()
+}
+
+func (p *PermissionCache) reload(ctx context.Context) error {
+	roles, err := p.store.GetRoles(ctx)
+	if err != nil {
+		return err
+	}
+
+	mapping := make(map[domain.UserRole][]domain.Permission, len(roles))
+	for _, role := range roles {
+		mapping[role.Name] = role.Permissions
+	}
+
+	p.mu.Lock()
+	p.roles = mapping
+	p.loadedAt = time.Now()
+	p.mu.Unlock()
+
+	return nil
+}
+
+func NewPermissionCache(store RoleStore, ttl time.Duration) *PermissionCache {
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	return &PermissionCache{
+		store: store,
+		ttl:   ttl,
+	}
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// APIKey is a long-lived credential owned by a user. Only a hash of the key is
+// stored; Prefix is the non-secret part shown back to the owner so keys can be
+// told apart. Scopes cap the permissions the key grants.
+type APIKey struct {
+	Id         string
+	UserId     string
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []Permission
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+type LoginAttempt struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+	ExpiresAt     time.Time
+}
@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+type ListVisibility string
+
+// Public lists show up in the public listing, unlisted ones can only be
+// reached through their share slug, and private ones only by their owner.
+const (
+	ListVisibilityPublic   ListVisibility = "public"
+	ListVisibilityUnlisted ListVisibility = "unlisted"
+	ListVisibilityPrivate  ListVisibility = "private"
+)
+
+func ListVisibilities() []ListVisibility {
+	return []ListVisibility{ListVisibilityPublic, ListVisibilityUnlisted, ListVisibilityPrivate}
+}
+
+const (
+	MovieListMaxItems   = 100
+	MovieListSortNewest = "-created_at"
+)
+
+type MovieListItem struct {
+	ImdbId  string
+	Note    string
+	AddedAt time.Time
+}
+
+// MovieList is a user's ordered selection of movies. Slug identifies the list
+// in share links and never changes.
+type MovieList struct {
+	Id          string
+	OwnerId     string
+	Title       string
+	Description string
+	Visibility  ListVisibility
+	Slug        string
+	Items       []MovieListItem
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Version     int64
+}
+
+func (l *MovieList) SortKey() string {
+	return l.CreatedAt.UTC().Format(time.RFC3339Nano)
+}
+
+// IndexOf returns the position of imdbId in the list, or -1.
+func (l *MovieList) IndexOf(imdbId string) int {
+	for i := range l.Items {
+		if l.Items[i].ImdbId == imdbId {
+			return i
+		}
+	}
+	return -1
+}
@@ -1,16 +1,62 @@
 package domain
 
-import "time"
+import (
+	"strconv"
+	"time"
+)
 
+// Movie is a catalogue entry. AverageRating and RatingCount summarise user
+// reviews and are only written by the review endpoints, never by movie edits.
 type Movie struct {
-	Id          string
-	ImdbId      string
-	Title       string
-	PosterPath  string
-	YoutubeId   string
-	Genres      []Genre
-	AdminReview string
-	Ranking     Ranking
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	Id            string
+	ImdbId        string
+	Title         string
+	PosterPath    string
+	YoutubeId     string
+	Genres        []Genre
+	AdminReview   string
+	Ranking       Ranking
+	AverageRating float64
+	RatingCount   int64
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Version       int64
+}
+
+// Sort orders accepted for movie listings. Without an explicit sort, text
+// searches are ordered by relevance and everything else by insertion.
+const (
+	MovieSortTitle   = "title"
+	MovieSortNewest  = "-created_at"
+	MovieSortRanking = "ranking"
+)
+
+func MovieSorts() []string {
+	return []string{MovieSortTitle, MovieSortNewest, MovieSortRanking}
+}
+
+// SortKey returns the value movie is ordered by under sort, as stored in a
+// Cursor.
+func (m *Movie) SortKey(sort string) string {
+	switch sort {
+	case MovieSortTitle:
+		return m.Title
+	case MovieSortNewest:
+		return m.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case MovieSortRanking:
+		return strconv.Itoa(m.Ranking.RankingValue)
+	default:
+		return ""
+	}
+}
+
+// MovieFilter narrows a movie listing. Query is a text search over title and
+// admin review; nil fields don't filter.
+type MovieFilter struct {
+	Query         string
+	GenreId       *int
+	RankingValue  *int
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Sort          string
 }
@@ -0,0 +1,10 @@
+package domain
+
+// Cursor is a keyset position: the sort order, the sort key and the id of the
+// row a page continues from. Backward cursors page towards the start.
+type Cursor struct {
+	Sort     string
+	Key      string
+	Id       string
+	Backward bool
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+const (
+	ReviewRatingMin = 1
+	ReviewRatingMax = 10
+	// ReviewSortNewest is the only order reviews are listed in.
+	ReviewSortNewest = "-created_at"
+)
+
+// Review is a user's rating of a movie with an optional written review. Each
+// user has at most one review per movie.
+type Review struct {
+	Id        string
+	ImdbId    string
+	UserId    string
+	Rating    int
+	Body      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (r *Review) SortKey() string {
+	return r.CreatedAt.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+type Permission string
+
+const (
+	PermissionMoviesWrite     Permission = "movies:write"
+	PermissionMoviesDelete    Permission = "movies:delete"
+	PermissionReviewsWrite    Permission = "reviews:write"
+	PermissionGenresWrite     Permission = "genres:write"
+	PermissionRankingsWrite   Permission = "rankings:write"
+	PermissionUsersManage     Permission = "users:manage"
+	PermissionRolesManage     Permission = "roles:manage"
+	PermissionContentModerate Permission = "content:moderate"
+)
+
+type Role struct {
+	Name        UserRole
+	Permissions []Permission
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func AllPermissions() []Permission {
+	return []Permission{
+		PermissionMoviesWrite,
+		PermissionMoviesDelete,
+		PermissionReviewsWrite,
+		PermissionGenresWrite,
+		PermissionRankingsWrite,
+		PermissionUsersManage,
+		PermissionRolesManage,
+		PermissionContentModerate,
+	}
+}
+
+func DefaultRoles() []Role {
+	return []Role{
+		{Name: UserRoleUser, Permissions: []Permission{}},
+		{Name: UserRoleCurator, Permissions: []Permission{PermissionMoviesWrite, PermissionReviewsWrite}},
+		{Name: UserRoleModerator, Permissions: []Permission{PermissionContentModerate}},
+		{Name: UserRoleAdmin, Permissions: AllPermissions()},
+	}
+}
@@ -4,10 +4,50 @@ import (
 	"time"
 )
 
+// RefreshToken is stored by hash only. Tokens issued by rotating one another
+// share a FamilyId, which doubles as the session id; RotatedAt is set once a
+// token has been exchanged so a second presentation can be recognised as
+// reuse.
 type RefreshToken struct {
+	Id               string
+	UserId           string
+	TokenHash        string
+	FamilyId         string
+	AccessTokenId    string
+	AccessExpiresAt  time.Time
+	UserAgent        string
+	IP               string
+	SessionCreatedAt time.Time
+	LastUsedAt       time.Time
+	ExpiresAt        time.Time
+	CreatedAt        time.Time
+	RotatedAt        *time.Time
+}
+
+// RevokedToken is a denylisted access token jti, kept until the token would
+// have expired anyway.
+type RevokedToken struct {
+	Jti       string
+	UserId    string
+	ExpiresAt time.Time
+	RevokedAt time.Time
+}
+
+type PasswordResetToken struct {
 	Id        string
 	UserId    string
-	Token     string
+	TokenHash string
 	ExpiresAt time.Time
 	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
+// OIDCState is kept server side between redirecting to a provider and its
+// callback, so the PKCE verifier and nonce never travel through the browser.
+type OIDCState struct {
+	State        string
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+	ExpiresAt    time.Time
 }
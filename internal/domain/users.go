@@ -1,14 +1,22 @@
 package domain
 
 import (
+	"strings"
 	"time"
 )
 
 type UserRole string
 
 const (
-	UserRoleUser  UserRole = "user"
-	UserRoleAdmin UserRole = "admin"
+	UserRoleUser      UserRole = "user"
+	UserRoleCurator   UserRole = "curator"
+	UserRoleModerator UserRole = "moderator"
+	UserRoleAdmin     UserRole = "admin"
+
+	// UserRoleRestricted is issued in tokens that carry no permissions, e.g.
+	// for unverified emails or admins who still have to enroll in MFA. It is
+	// never stored.
+	UserRoleRestricted UserRole = "restricted"
 )
 
 type User struct {
@@ -21,4 +29,34 @@ type User struct {
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	FavoriteGenres []Genre
+	Version        int64
+	EmailVerified  bool
+	VerifiedAt     *time.Time
+	MFA            UserMFA
+	Identities     []UserIdentity
+}
+
+// UserIdentity links an account at an external OIDC provider to the user.
+// Users created through a provider have no password until they reset one.
+type UserIdentity struct {
+	Provider string
+	Subject  string
+	LinkedAt time.Time
+}
+
+// UserMFA holds TOTP state. PendingSecret is set between enrollment and
+// confirmation; RecoveryCodes are SHA-256 hashes; LastStep guards against
+// replaying a code within its validity window.
+type UserMFA struct {
+	Enabled       bool
+	Secret        string
+	PendingSecret string
+	RecoveryCodes []string
+	LastStep      int64
+}
+
+// NormalizeEmail is applied to every email before it is stored or looked up,
+// since the unique index on user emails is case-sensitive.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
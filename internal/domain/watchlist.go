@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// Cursor sort orders for the watchlist and watch history, newest first.
+const (
+	WatchlistSortNewest = "-added_at"
+	HistorySortNewest   = "-watched_at"
+)
+
+// WatchlistItem is a movie a user wants to see. Movie is filled in when the
+// item is read back and is nil if the movie has since been deleted.
+type WatchlistItem struct {
+	Id      string
+	UserId  string
+	ImdbId  string
+	AddedAt time.Time
+	Movie   *Movie
+}
+
+func (w *WatchlistItem) SortKey() string {
+	return w.AddedAt.UTC().Format(time.RFC3339Nano)
+}
+
+// WatchHistoryEntry records one viewing. A movie can be logged more than once,
+// and Rating is a private note that does not count towards reviews.
+type WatchHistoryEntry struct {
+	Id        string
+	UserId    string
+	ImdbId    string
+	WatchedAt time.Time
+	Rating    *int
+	CreatedAt time.Time
+	Movie     *Movie
+}
+
+func (w *WatchHistoryEntry) SortKey() string {
+	return w.WatchedAt.UTC().Format(time.RFC3339Nano)
+}
@@ -4,13 +4,19 @@ import "github.com/saleh-ghazimoradi/Projectopher/internal/helper"
 
 type AdminReviewUpdateReq struct {
 	AdminReview string `json:"admin_review"`
+	Version     *int64 `json:"version"`
 }
 
 type AdminReviewResp struct {
 	RankingName string `json:"ranking_name"`
 	AdminReview string `json:"admin_review"`
+	Version     int64  `json:"version"`
+}
+
+func validateAdminReview(v *helper.Validator, adminReview string) {
+	v.Check(adminReview != "", "adminReview", "adminReview must be provided")
 }
 
 func ValidateAdminReview(v *helper.Validator, req *AdminReviewUpdateReq) {
-	v.Check(req.AdminReview != "", "adminReview", "adminReview must be provided")
+	validateAdminReview(v, req.AdminReview)
 }
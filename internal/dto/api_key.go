@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"time"
+)
+
+type CreateAPIKeyReq struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type APIKeyResp struct {
+	Id         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyResp is the only response that ever carries the key itself.
+type CreatedAPIKeyResp struct {
+	APIKeyResp
+	Key string `json:"key"`
+}
+
+func ToAPIKeyResp(key *domain.APIKey) *APIKeyResp {
+	scopes := make([]string, len(key.Scopes))
+	for i, s := range key.Scopes {
+		scopes[i] = string(s)
+	}
+
+	return &APIKeyResp{
+		Id:         key.Id,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func ValidateCreateAPIKeyReq(v *helper.Validator, req *CreateAPIKeyReq) {
+	v.Check(req.Name != "", "name", "must be provided")
+	v.Check(len(req.Name) <= 100, "name", "must not be more than 100 characters long")
+	v.Check(helper.Unique(req.Scopes), "scopes", "must not contain duplicate values")
+
+	known := domain.AllPermissions()
+	for _, s := range req.Scopes {
+		v.Check(helper.PermittedValue(domain.Permission(s), known...), "scopes", "must only contain known permissions")
+	}
+
+	if req.ExpiresAt != nil {
+		v.Check(req.ExpiresAt.After(time.Now()), "expires_at", "must be in the future")
+	}
+}
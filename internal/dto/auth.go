@@ -21,10 +21,30 @@ type RefreshTokenReq struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+type ForgotPasswordReq struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordReq struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
 type AuthResp struct {
-	User         UserResp `json:"user"`
-	AccessToken  string   `json:"access_token"`
-	RefreshToken string   `json:"refresh_token"`
+	User                  *UserResp `json:"user,omitempty"`
+	AccessToken           string    `json:"access_token,omitempty"`
+	RefreshToken          string    `json:"refresh_token,omitempty"`
+	MFARequired           bool      `json:"mfa_required,omitempty"`
+	MFAToken              string    `json:"mfa_token,omitempty"`
+	MFAEnrollmentRequired bool      `json:"mfa_enrollment_required,omitempty"`
+}
+
+type VerifyEmailReq struct {
+	Token string `json:"token"`
+}
+
+type ResendVerificationReq struct {
+	Email string `json:"email"`
 }
 
 func validateEmail(v *helper.Validator, email string) {
@@ -35,7 +55,7 @@ func validateEmail(v *helper.Validator, email string) {
 func validatePassword(v *helper.Validator, password string) {
 	v.Check(password != "", "password", "must be provided")
 	v.Check(len(password) >= 8, "password", "must be at least 8 bytes long")
-	v.Check(len(password) <= 72, "password", "must not be more than 72 bytes long")
+	v.Check(len(password) <= 256, "password", "must not be more than 256 bytes long")
 }
 
 func validateUserNames(v *helper.Validator, firstName string, lastName string) {
@@ -49,7 +69,7 @@ func validateUserNames(v *helper.Validator, firstName string, lastName string) {
 
 func validateRole(v *helper.Validator, role string) {
 	v.Check(role != "", "role", "must be provided")
-	v.Check(helper.PermittedValue(role, "user", "admin"), "role", "must be either user or admin")
+	v.Check(len(role) <= 64, "role", "must not be more than 64 bytes long")
 }
 
 func ValidateLogin(v *helper.Validator, req *LoginReq) {
@@ -66,3 +86,20 @@ func ValidateRegister(v *helper.Validator, req *RegisterReq) {
 	validateEmail(v, req.Email)
 	validatePassword(v, req.Password)
 }
+
+func ValidateForgotPassword(v *helper.Validator, req *ForgotPasswordReq) {
+	validateEmail(v, req.Email)
+}
+
+func ValidateResetPassword(v *helper.Validator, req *ResetPasswordReq) {
+	v.Check(req.Token != "", "token", "must be provided")
+	validatePassword(v, req.Password)
+}
+
+func ValidateVerifyEmail(v *helper.Validator, req *VerifyEmailReq) {
+	v.Check(req.Token != "", "token", "must be provided")
+}
+
+func ValidateResendVerification(v *helper.Validator, req *ResendVerificationReq) {
+	validateEmail(v, req.Email)
+}
@@ -1,6 +1,34 @@
 package dto
 
+import "github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+
 type Genre struct {
 	GenreId   int    `json:"genre_id"`
 	GenreName string `json:"genre_name"`
 }
+
+type CreateGenreReq struct {
+	GenreId   int    `json:"genre_id"`
+	GenreName string `json:"genre_name"`
+}
+
+type UpdateGenreReq struct {
+	GenreName *string `json:"genre_name"`
+}
+
+func validateGenreName(v *helper.Validator, genreName string) {
+	v.Check(genreName != "", "genre_name", "must be provided")
+	v.Check(len(genreName) <= 50, "genre_name", "must not be more than 50 characters")
+}
+
+func ValidateCreateGenreReq(v *helper.Validator, req *CreateGenreReq) {
+	v.Check(req.GenreId > 0, "genre_id", "must be a positive integer")
+	validateGenreName(v, req.GenreName)
+}
+
+func ValidateUpdateGenreReq(v *helper.Validator, req *UpdateGenreReq) {
+	v.Check(req.GenreName != nil, "genre_name", "must be provided")
+	if req.GenreName != nil {
+		validateGenreName(v, *req.GenreName)
+	}
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+
+type MFAEnrollResp struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+type MFARecoveryCodesResp struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type MFACodeReq struct {
+	Code string `json:"code"`
+}
+
+type MFAVerifyReq struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+func validateMFACode(v *helper.Validator, code string) {
+	v.Check(code != "", "code", "must be provided")
+	v.Check(len(code) <= 16, "code", "must not be more than 16 characters long")
+}
+
+func ValidateMFACodeReq(v *helper.Validator, req *MFACodeReq) {
+	validateMFACode(v, req.Code)
+}
+
+func ValidateMFAVerifyReq(v *helper.Validator, req *MFAVerifyReq) {
+	v.Check(req.MFAToken != "", "mfa_token", "must be provided")
+	validateMFACode(v, req.Code)
+}
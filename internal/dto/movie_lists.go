@@ -0,0 +1,183 @@
+package dto
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"time"
+)
+
+type MovieListItemReq struct {
+	ImdbId string `json:"imdb_id"`
+	Note   string `json:"note"`
+}
+
+type CreateMovieListReq struct {
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Visibility  string             `json:"visibility"`
+	Items       []MovieListItemReq `json:"items"`
+}
+
+type UpdateMovieListReq struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	Visibility  *string `json:"visibility"`
+	Version     *int64  `json:"version"`
+}
+
+// AddMovieListItemReq inserts a movie at Position, counted from 1, or at the
+// end when Position is omitted.
+type AddMovieListItemReq struct {
+	ImdbId   string `json:"imdb_id"`
+	Note     string `json:"note"`
+	Position *int   `json:"position"`
+}
+
+// ReorderMovieListReq gives the new order of every movie on the list.
+type ReorderMovieListReq struct {
+	ImdbIds []string `json:"imdb_ids"`
+	Version *int64   `json:"version"`
+}
+
+// ListMovieListsReq is read from the query string of GET /v1/lists and pages
+// the same way as ListMoviesReq.
+type ListMovieListsReq struct {
+	Page         int64
+	Limit        int64
+	Cursor       *string
+	IncludeTotal bool
+}
+
+type MovieListItemResp struct {
+	Position int        `json:"position"`
+	ImdbId   string     `json:"imdb_id"`
+	Note     string     `json:"note"`
+	AddedAt  time.Time  `json:"added_at"`
+	Movie    *MovieResp `json:"movie"`
+}
+
+type MovieListResp struct {
+	Id          string              `json:"id"`
+	OwnerId     string              `json:"owner_id"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Visibility  string              `json:"visibility"`
+	Slug        string              `json:"slug"`
+	Items       []MovieListItemResp `json:"items"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	Version     int64               `json:"version"`
+}
+
+// ToMovieListResp joins in the movies found in movies, keyed by imdb id. Items
+// whose movie has since been deleted keep a null movie.
+func ToMovieListResp(list *domain.MovieList, movies map[string]*domain.Movie) *MovieListResp {
+	items := make([]MovieListItemResp, len(list.Items))
+	for i, item := range list.Items {
+		items[i] = MovieListItemResp{
+			Position: i + 1,
+			ImdbId:   item.ImdbId,
+			Note:     item.Note,
+			AddedAt:  item.AddedAt,
+		}
+
+		if movie, ok := movies[item.ImdbId]; ok {
+			items[i].Movie = ToMovieResp(movie)
+		}
+	}
+
+	return &MovieListResp{
+		Id:          list.Id,
+		OwnerId:     list.OwnerId,
+		Title:       list.Title,
+		Description: list.Description,
+		Visibility:  string(list.Visibility),
+		Slug:        list.Slug,
+		Items:       items,
+		CreatedAt:   list.CreatedAt,
+		UpdatedAt:   list.UpdatedAt,
+		Version:     list.Version,
+	}
+}
+
+func ApplyUpdateMovieListReq(list *domain.MovieList, req *UpdateMovieListReq) {
+	if req.Title != nil {
+		list.Title = *req.Title
+	}
+
+	if req.Description != nil {
+		list.Description = *req.Description
+	}
+
+	if req.Visibility != nil {
+		list.Visibility = domain.ListVisibility(*req.Visibility)
+	}
+}
+
+func validateListTitle(v *helper.Validator, title string) {
+	v.Check(title != "", "title", "must be provided")
+	v.Check(len(title) <= 200, "title", "must not be more than 200 characters long")
+}
+
+func validateListDescription(v *helper.Validator, description string) {
+	v.Check(len(description) <= 2000, "description", "must not be more than 2000 characters long")
+}
+
+func validateListVisibility(v *helper.Validator, visibility string) {
+	v.Check(helper.PermittedValue(domain.ListVisibility(visibility), domain.ListVisibilities()...), "visibility", "must be one of public, unlisted or private")
+}
+
+func validateListNote(v *helper.Validator, note string) {
+	v.Check(len(note) <= 500, "note", "must not be more than 500 characters long")
+}
+
+func ValidateCreateMovieListReq(v *helper.Validator, req *CreateMovieListReq) {
+	validateListTitle(v, req.Title)
+	validateListDescription(v, req.Description)
+	validateListVisibility(v, req.Visibility)
+	v.Check(len(req.Items) <= domain.MovieListMaxItems, "items", "must not contain more than 100 movies")
+
+	imdbIds := make([]string, len(req.Items))
+	for i, item := range req.Items {
+		validateImdbId(v, item.ImdbId)
+		validateListNote(v, item.Note)
+		imdbIds[i] = item.ImdbId
+	}
+	v.Check(helper.Unique(imdbIds), "items", "must not contain the same movie twice")
+}
+
+func ValidateUpdateMovieListReq(v *helper.Validator, req *UpdateMovieListReq) {
+	v.Check(req.Title != nil || req.Description != nil || req.Visibility != nil, "body", "must contain at least one field to update")
+
+	if req.Title != nil {
+		validateListTitle(v, *req.Title)
+	}
+
+	if req.Description != nil {
+		validateListDescription(v, *req.Description)
+	}
+
+	if req.Visibility != nil {
+		validateListVisibility(v, *req.Visibility)
+	}
+}
+
+func ValidateAddMovieListItemReq(v *helper.Validator, req *AddMovieListItemReq) {
+	validateImdbId(v, req.ImdbId)
+	validateListNote(v, req.Note)
+
+	if req.Position != nil {
+		v.Check(*req.Position >= 1, "position", "must be at least 1")
+	}
+}
+
+func ValidateReorderMovieListReq(v *helper.Validator, req *ReorderMovieListReq) {
+	v.Check(req.ImdbIds != nil, "imdb_ids", "must be provided")
+	v.Check(helper.Unique(req.ImdbIds), "imdb_ids", "must not contain duplicate values")
+}
+
+func ValidateListMovieListsReq(v *helper.Validator, req *ListMovieListsReq) {
+	if req.Cursor != nil {
+		v.Check(req.Page == 0, "page", "must not be combined with cursor")
+	}
+}
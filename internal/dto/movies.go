@@ -3,6 +3,7 @@ package dto
 import (
 	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"time"
 )
 
 type CreateMovieReq struct {
@@ -15,15 +16,55 @@ type CreateMovieReq struct {
 	Ranking     Ranking `json:"ranking"`
 }
 
-type MovieResp struct {
-	Id          string  `json:"id"`
-	ImdbId      string  `json:"imdb_id"`
+type ReplaceMovieReq struct {
 	Title       string  `json:"title"`
 	PosterPath  string  `json:"poster_path"`
 	YoutubeId   string  `json:"youtube_id"`
-	Genre       []Genre `json:"genre"`
 	AdminReview string  `json:"admin_review"`
+	Genre       []Genre `json:"genre"`
 	Ranking     Ranking `json:"ranking"`
+	Version     *int64  `json:"version"`
+}
+
+type UpdateMovieReq struct {
+	Title       *string  `json:"title"`
+	PosterPath  *string  `json:"poster_path"`
+	YoutubeId   *string  `json:"youtube_id"`
+	AdminReview *string  `json:"admin_review"`
+	Genre       []Genre  `json:"genre"`
+	Ranking     *Ranking `json:"ranking"`
+	Version     *int64   `json:"version"`
+}
+
+// ListMoviesReq is read from the query string of GET /v1/movies. A non-nil
+// Cursor, even an empty one, selects cursor paging instead of Page.
+type ListMoviesReq struct {
+	Page          int64
+	Limit         int64
+	Q             string
+	Genre         *int
+	Ranking       *int
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Sort          string
+	Cursor        *string
+	IncludeTotal  bool
+}
+
+type MovieResp struct {
+	Id            string    `json:"id"`
+	ImdbId        string    `json:"imdb_id"`
+	Title         string    `json:"title"`
+	PosterPath    string    `json:"poster_path"`
+	YoutubeId     string    `json:"youtube_id"`
+	Genre         []Genre   `json:"genre"`
+	AdminReview   string    `json:"admin_review"`
+	Ranking       Ranking   `json:"ranking"`
+	AverageRating float64   `json:"average_rating"`
+	RatingCount   int64     `json:"rating_count"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Version       int64     `json:"version"`
 }
 
 func ToMovieResp(movie *domain.Movie) *MovieResp {
@@ -47,6 +88,11 @@ func ToMovieResp(movie *domain.Movie) *MovieResp {
 			RankingValue: movie.Ranking.RankingValue,
 			RankingName:  movie.Ranking.RankingName,
 		},
+		AverageRating: movie.AverageRating,
+		RatingCount:   movie.RatingCount,
+		CreatedAt:     movie.CreatedAt,
+		UpdatedAt:     movie.UpdatedAt,
+		Version:       movie.Version,
 	}
 }
 
@@ -59,19 +105,12 @@ func ToMoviesResp(movies []domain.Movie) []MovieResp {
 }
 
 func FromCreateMovieReq(dto *CreateMovieReq) *domain.Movie {
-	genres := make([]domain.Genre, len(dto.Genre))
-	for i, g := range dto.Genre {
-		genres[i] = domain.Genre{
-			GenreId:   g.GenreId,
-			GenreName: g.GenreName,
-		}
-	}
 	return &domain.Movie{
 		ImdbId:      dto.ImdbId,
 		Title:       dto.Title,
 		PosterPath:  dto.PosterPath,
 		YoutubeId:   dto.YoutubeId,
-		Genres:      genres,
+		Genres:      FromGenresReq(dto.Genre),
 		AdminReview: dto.AdminReview,
 		Ranking: domain.Ranking{
 			RankingValue: dto.Ranking.RankingValue,
@@ -80,6 +119,58 @@ func FromCreateMovieReq(dto *CreateMovieReq) *domain.Movie {
 	}
 }
 
+func FromGenresReq(input []Genre) []domain.Genre {
+	genres := make([]domain.Genre, len(input))
+	for i, g := range input {
+		genres[i] = domain.Genre{
+			GenreId:   g.GenreId,
+			GenreName: g.GenreName,
+		}
+	}
+	return genres
+}
+
+func ApplyReplaceMovieReq(movie *domain.Movie, req *ReplaceMovieReq) {
+	movie.Title = req.Title
+	movie.PosterPath = req.PosterPath
+	movie.YoutubeId = req.YoutubeId
+	movie.AdminReview = req.AdminReview
+	movie.Genres = FromGenresReq(req.Genre)
+	movie.Ranking = domain.Ranking{
+		RankingValue: req.Ranking.RankingValue,
+		RankingName:  req.Ranking.RankingName,
+	}
+}
+
+func ApplyUpdateMovieReq(movie *domain.Movie, req *UpdateMovieReq) {
+	if req.Title != nil {
+		movie.Title = *req.Title
+	}
+
+	if req.PosterPath != nil {
+		movie.PosterPath = *req.PosterPath
+	}
+
+	if req.YoutubeId != nil {
+		movie.YoutubeId = *req.YoutubeId
+	}
+
+	if req.AdminReview != nil {
+		movie.AdminReview = *req.AdminReview
+	}
+
+	if req.Genre != nil {
+		movie.Genres = FromGenresReq(req.Genre)
+	}
+
+	if req.Ranking != nil {
+		movie.Ranking = domain.Ranking{
+			RankingValue: req.Ranking.RankingValue,
+			RankingName:  req.Ranking.RankingName,
+		}
+	}
+}
+
 func ToGenreResp(genre *domain.Genre) *Genre {
 	return &Genre{
 		GenreId:   genre.GenreId,
@@ -123,6 +214,10 @@ func validateGenre(v *helper.Validator, genre []Genre) {
 
 func validateRanking(v *helper.Validator, ranking *Ranking) {
 	v.Check(ranking != nil, "ranking", "ranking must be provided")
+	if ranking != nil {
+		v.Check(ranking.RankingValue > 0, "ranking_value", "must be a positive integer")
+		validateRankingName(v, ranking.RankingName)
+	}
 }
 
 func ValidateCreateMovieReq(v *helper.Validator, req *CreateMovieReq) {
@@ -133,3 +228,67 @@ func ValidateCreateMovieReq(v *helper.Validator, req *CreateMovieReq) {
 	validateGenre(v, req.Genre)
 	validateRanking(v, &req.Ranking)
 }
+
+func ValidateReplaceMovieReq(v *helper.Validator, req *ReplaceMovieReq) {
+	validateTitle(v, req.Title)
+	validatePosterPath(v, req.PosterPath)
+	validateYoutubeId(v, req.YoutubeId)
+	validateGenre(v, req.Genre)
+	validateRanking(v, &req.Ranking)
+}
+
+func ValidateUpdateMovieReq(v *helper.Validator, req *UpdateMovieReq) {
+	v.Check(req.Title != nil || req.PosterPath != nil || req.YoutubeId != nil || req.AdminReview != nil || req.Genre != nil || req.Ranking != nil, "body", "must contain at least one field to update")
+
+	if req.Title != nil {
+		validateTitle(v, *req.Title)
+	}
+
+	if req.PosterPath != nil {
+		validatePosterPath(v, *req.PosterPath)
+	}
+
+	if req.YoutubeId != nil {
+		validateYoutubeId(v, *req.YoutubeId)
+	}
+
+	if req.AdminReview != nil {
+		validateAdminReview(v, *req.AdminReview)
+	}
+
+	if req.Genre != nil {
+		validateGenre(v, req.Genre)
+	}
+
+	if req.Ranking != nil {
+		validateRanking(v, req.Ranking)
+	}
+}
+
+func ValidateListMoviesReq(v *helper.Validator, req *ListMoviesReq) {
+	v.Check(len(req.Q) <= 200, "q", "must not be more than 200 characters long")
+
+	if req.Sort != "" {
+		v.Check(helper.PermittedValue(req.Sort, domain.MovieSorts()...), "sort", "must be one of title, -created_at or ranking")
+	}
+
+	if req.CreatedAfter != nil && req.CreatedBefore != nil {
+		v.Check(req.CreatedAfter.Before(*req.CreatedBefore), "created_after", "must be before created_before")
+	}
+
+	if req.Cursor != nil {
+		v.Check(req.Page == 0, "page", "must not be combined with cursor")
+		v.Check(req.Q == "" || req.Sort != "", "sort", "must be set when paging search results by cursor")
+	}
+}
+
+func (req *ListMoviesReq) Filter() *domain.MovieFilter {
+	return &domain.MovieFilter{
+		Query:         req.Q,
+		GenreId:       req.Genre,
+		RankingValue:  req.Ranking,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		Sort:          req.Sort,
+	}
+}
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"time"
+)
+
+// OIDCAuthorizeResp only exposes the authorization URL. State travels to the
+// browser in an HttpOnly cookie so a callback can't be replayed from another
+// browser.
+type OIDCAuthorizeResp struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	State            string    `json:"-"`
+	ExpiresAt        time.Time `json:"-"`
+}
+
+// OIDCCallbackReq carries the code and state the provider appended to the
+// redirect URL.
+type OIDCCallbackReq struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+func ValidateOIDCCallbackReq(v *helper.Validator, req *OIDCCallbackReq) {
+	v.Check(req.Code != "", "code", "must be provided")
+	v.Check(len(req.Code) <= 2048, "code", "must not be more than 2048 characters long")
+	v.Check(req.State != "", "state", "must be provided")
+	v.Check(len(req.State) <= 128, "state", "must not be more than 128 characters long")
+}
@@ -1,6 +1,52 @@
 package dto
 
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+)
+
 type Ranking struct {
 	RankingValue int    `json:"ranking_value"`
 	RankingName  string `json:"ranking_name"`
 }
+
+type CreateRankingReq struct {
+	RankingValue int    `json:"ranking_value"`
+	RankingName  string `json:"ranking_name"`
+}
+
+type UpdateRankingReq struct {
+	RankingName *string `json:"ranking_name"`
+}
+
+func ToRankingResp(ranking *domain.Ranking) *Ranking {
+	return &Ranking{
+		RankingValue: ranking.RankingValue,
+		RankingName:  ranking.RankingName,
+	}
+}
+
+func ToRankingsResp(rankings []domain.Ranking) []Ranking {
+	DTOs := make([]Ranking, len(rankings))
+	for i, r := range rankings {
+		DTOs[i] = *ToRankingResp(&r)
+	}
+	return DTOs
+}
+
+func validateRankingName(v *helper.Validator, rankingName string) {
+	v.Check(rankingName != "", "ranking_name", "must be provided")
+	v.Check(len(rankingName) <= 50, "ranking_name", "must not be more than 50 characters")
+}
+
+func ValidateCreateRankingReq(v *helper.Validator, req *CreateRankingReq) {
+	v.Check(req.RankingValue > 0, "ranking_value", "must be a positive integer")
+	validateRankingName(v, req.RankingName)
+}
+
+func ValidateUpdateRankingReq(v *helper.Validator, req *UpdateRankingReq) {
+	v.Check(req.RankingName != nil, "ranking_name", "must be provided")
+	if req.RankingName != nil {
+		validateRankingName(v, *req.RankingName)
+	}
+}
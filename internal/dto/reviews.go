@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"time"
+)
+
+type CreateReviewReq struct {
+	Rating int    `json:"rating"`
+	Body   string `json:"body"`
+}
+
+type UpdateReviewReq struct {
+	Rating *int    `json:"rating"`
+	Body   *string `json:"body"`
+}
+
+type ReviewResp struct {
+	Id        string    `json:"id"`
+	ImdbId    string    `json:"imdb_id"`
+	UserId    string    `json:"user_id"`
+	Rating    int       `json:"rating"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func ToReviewResp(review *domain.Review) *ReviewResp {
+	return &ReviewResp{
+		Id:        review.Id,
+		ImdbId:    review.ImdbId,
+		UserId:    review.UserId,
+		Rating:    review.Rating,
+		Body:      review.Body,
+		CreatedAt: review.CreatedAt,
+		UpdatedAt: review.UpdatedAt,
+	}
+}
+
+func ToReviewsResp(reviews []domain.Review) []ReviewResp {
+	DTOs := make([]ReviewResp, len(reviews))
+	for i := range reviews {
+		DTOs[i] = *ToReviewResp(&reviews[i])
+	}
+	return DTOs
+}
+
+func ApplyUpdateReviewReq(review *domain.Review, req *UpdateReviewReq) {
+	if req.Rating != nil {
+		review.Rating = *req.Rating
+	}
+
+	if req.Body != nil {
+		review.Body = *req.Body
+	}
+}
+
+func validateRating(v *helper.Validator, rating int) {
+	v.Check(rating >= domain.ReviewRatingMin && rating <= domain.ReviewRatingMax, "rating", "must be between 1 and 10")
+}
+
+func validateReviewBody(v *helper.Validator, body string) {
+	v.Check(len(body) <= 5000, "body", "must not be more than 5000 characters long")
+}
+
+func ValidateCreateReviewReq(v *helper.Validator, req *CreateReviewReq) {
+	validateRating(v, req.Rating)
+	validateReviewBody(v, req.Body)
+}
+
+func ValidateUpdateReviewReq(v *helper.Validator, req *UpdateReviewReq) {
+	v.Check(req.Rating != nil || req.Body != nil, "body", "must contain at least one field to update")
+
+	if req.Rating != nil {
+		validateRating(v, *req.Rating)
+	}
+
+	if req.Body != nil {
+		validateReviewBody(v, *req.Body)
+	}
+}
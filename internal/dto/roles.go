@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"time"
+)
+
+type RoleResp struct {
+	Name        string    `json:"name"`
+	Permissions []string  `json:"permissions"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type UpdateRolePermissionsReq struct {
+	Permissions []string `json:"permissions"`
+}
+
+type AssignRoleReq struct {
+	Role    string `json:"role"`
+	Version *int64 `json:"version"`
+}
+
+func ToRoleResp(role *domain.Role) *RoleResp {
+	permissions := make([]string, len(role.Permissions))
+	for i, p := range role.Permissions {
+		permissions[i] = string(p)
+	}
+
+	return &RoleResp{
+		Name:        string(role.Name),
+		Permissions: permissions,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
+
+func ToRolesResp(roles []domain.Role) []RoleResp {
+	DTOs := make([]RoleResp, len(roles))
+	for i, r := range roles {
+		DTOs[i] = *ToRoleResp(&r)
+	}
+	return DTOs
+}
+
+func ValidateUpdateRolePermissionsReq(v *helper.Validator, req *UpdateRolePermissionsReq) {
+	v.Check(req.Permissions != nil, "permissions", "must be provided")
+	v.Check(helper.Unique(req.Permissions), "permissions", "must not contain duplicate values")
+
+	known := domain.AllPermissions()
+	for _, p := range req.Permissions {
+		v.Check(helper.PermittedValue(domain.Permission(p), known...), "permissions", "must only contain known permissions")
+	}
+}
+
+func ValidateAssignRoleReq(v *helper.Validator, req *AssignRoleReq) {
+	validateRole(v, req.Role)
+}
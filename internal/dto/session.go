@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// ClientInfo describes the client a session was created from.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+type SessionResp struct {
+	Id         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
@@ -14,11 +14,27 @@ type UserResp struct {
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
 	FavoriteGenres []Genre   `json:"favorite_genres"`
+	Version        int64     `json:"version"`
+	EmailVerified  bool      `json:"email_verified"`
+	MFAEnabled     bool      `json:"mfa_enabled"`
 }
 
 type UpdateUserReq struct {
 	FirstName *string `json:"first_name"`
 	LastName  *string `json:"last_name"`
+	Version   *int64  `json:"version"`
+}
+
+type LoginLockResp struct {
+	Failures      int        `json:"failures"`
+	Locked        bool       `json:"locked"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+}
+
+type ChangePasswordReq struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
 }
 
 func validateFirstName(v *helper.Validator, firstName *string) {
@@ -39,3 +55,9 @@ func ValidateUserUpdateReq(v *helper.Validator, req *UpdateUserReq) {
 	validateFirstName(v, req.FirstName)
 	validateLastName(v, req.LastName)
 }
+
+func ValidateChangePasswordReq(v *helper.Validator, req *ChangePasswordReq) {
+	v.Check(req.CurrentPassword != "", "current_password", "must be provided")
+	validatePassword(v, req.NewPassword)
+	v.Check(req.NewPassword != req.CurrentPassword, "new_password", "must differ from the current password")
+}
@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"time"
+)
+
+type AddWatchlistItemReq struct {
+	ImdbId string `json:"imdb_id"`
+}
+
+// AddWatchHistoryReq logs a viewing. WatchedAt defaults to now.
+type AddWatchHistoryReq struct {
+	ImdbId    string     `json:"imdb_id"`
+	WatchedAt *time.Time `json:"watched_at"`
+	Rating    *int       `json:"rating"`
+}
+
+// Movie is null when the movie has been deleted since it was added.
+type WatchlistItemResp struct {
+	Id      string     `json:"id"`
+	ImdbId  string     `json:"imdb_id"`
+	AddedAt time.Time  `json:"added_at"`
+	Movie   *MovieResp `json:"movie"`
+}
+
+type WatchHistoryEntryResp struct {
+	Id        string     `json:"id"`
+	ImdbId    string     `json:"imdb_id"`
+	WatchedAt time.Time  `json:"watched_at"`
+	Rating    *int       `json:"rating"`
+	CreatedAt time.Time  `json:"created_at"`
+	Movie     *MovieResp `json:"movie"`
+}
+
+func ToWatchlistItemResp(item *domain.WatchlistItem) *WatchlistItemResp {
+	resp := &WatchlistItemResp{
+		Id:      item.Id,
+		ImdbId:  item.ImdbId,
+		AddedAt: item.AddedAt,
+	}
+
+	if item.Movie != nil {
+		resp.Movie = ToMovieResp(item.Movie)
+	}
+
+	return resp
+}
+
+func ToWatchlistResp(items []domain.WatchlistItem) []WatchlistItemResp {
+	DTOs := make([]WatchlistItemResp, len(items))
+	for i := range items {
+		DTOs[i] = *ToWatchlistItemResp(&items[i])
+	}
+	return DTOs
+}
+
+func ToWatchHistoryEntryResp(entry *domain.WatchHistoryEntry) *WatchHistoryEntryResp {
+	resp := &WatchHistoryEntryResp{
+		Id:        entry.Id,
+		ImdbId:    entry.ImdbId,
+		WatchedAt: entry.WatchedAt,
+		Rating:    entry.Rating,
+		CreatedAt: entry.CreatedAt,
+	}
+
+	if entry.Movie != nil {
+		resp.Movie = ToMovieResp(entry.Movie)
+	}
+
+	return resp
+}
+
+func ToWatchHistoryResp(entries []domain.WatchHistoryEntry) []WatchHistoryEntryResp {
+	DTOs := make([]WatchHistoryEntryResp, len(entries))
+	for i := range entries {
+		DTOs[i] = *ToWatchHistoryEntryResp(&entries[i])
+	}
+	return DTOs
+}
+
+func ValidateAddWatchlistItemReq(v *helper.Validator, req *AddWatchlistItemReq) {
+	validateImdbId(v, req.ImdbId)
+}
+
+func ValidateAddWatchHistoryReq(v *helper.Validator, req *AddWatchHistoryReq) {
+	validateImdbId(v, req.ImdbId)
+
+	if req.WatchedAt != nil {
+		v.Check(!req.WatchedAt.After(time.Now()), "watched_at", "must not be in the future")
+	}
+
+	if req.Rating != nil {
+		validateRating(v, *req.Rating)
+	}
+}
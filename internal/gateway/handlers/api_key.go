@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/authz"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/service"
+	"net/http"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+func (a *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if !isCaller(r, id) {
+		helper.ForbiddenResponse(w, "You can only create your own api keys")
+		return
+	}
+
+	var payload dto.CreateAPIKeyReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "invalid payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateCreateAPIKeyReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	apiKey, err := a.apiKeyService.CreateAPIKey(r.Context(), id, &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Failed to fetch a user")
+		case errors.Is(err, service.ErrAPIKeyScopeNotHeld), errors.Is(err, service.ErrAPIKeyNotAllowed):
+			helper.ForbiddenResponse(w, err.Error())
+		default:
+			helper.InternalServerError(w, "Failed to create api key", err)
+		}
+		return
+	}
+
+	helper.CreatedResponse(w, "Api key successfully created", apiKey)
+}
+
+func (a *APIKeyHandler) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if !authz.Can(r.Context(), authz.ActionRead, authz.User(id)) {
+		helper.ForbiddenResponse(w, "You are not authorized to access this resource")
+		return
+	}
+
+	apiKeys, err := a.apiKeyService.GetAPIKeys(r.Context(), id)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to fetch api keys", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Api keys successfully retrieved", apiKeys)
+}
+
+func (a *APIKeyHandler) DeleteAPIKey(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	keyId := httprouter.ParamsFromContext(r.Context()).ByName("key_id")
+	if id == "" || keyId == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id and key id are required"))
+		return
+	}
+
+	if !authz.Can(r.Context(), authz.ActionDelete, authz.User(id)) {
+		helper.ForbiddenResponse(w, "You are not authorized to access this resource")
+		return
+	}
+
+	if err := a.apiKeyService.DeleteAPIKey(r.Context(), id, keyId); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Api key not found")
+		case errors.Is(err, service.ErrAPIKeyNotAllowed):
+			helper.ForbiddenResponse(w, err.Error())
+		default:
+			helper.InternalServerError(w, "Failed to delete api key", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Api key successfully deleted", nil)
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"crypto/subtle"
 	"errors"
+	"github.com/julienschmidt/httprouter"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/service"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type AuthHandler struct {
@@ -27,13 +33,15 @@ func (a *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := a.authService.Register(r.Context(), &payload)
+	user, err := a.authService.Register(r.Context(), &payload, clientInfo(r))
 	if err != nil {
 		switch {
 		case errors.Is(err, repository.ErrRecordNotFound):
 			helper.BadRequestResponse(w, "Registration failed", err)
 		case errors.Is(err, repository.ErrDuplicateEmail):
 			helper.EditConflictResponse(w, "Registration failed", err)
+		case errors.Is(err, utils.ErrPasswordTooLong):
+			helper.BadRequestResponse(w, "Registration failed", err)
 		default:
 			helper.InternalServerError(w, "Failed to register user", err)
 		}
@@ -56,9 +64,124 @@ func (a *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	login, err := a.authService.Login(r.Context(), &payload)
+	login, err := a.authService.Login(r.Context(), &payload, clientInfo(r))
 	if err != nil {
-		helper.InternalServerError(w, "Failed to login", err)
+		var lockedErr *service.LoginLockedError
+		switch {
+		case errors.As(err, &lockedErr):
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+			helper.RateLimitExceededResponse(w, "Too many failed login attempts, try again later")
+		case errors.Is(err, service.ErrInvalidCredentials):
+			helper.UnauthorizedResponse(w, "Invalid credentials")
+		case errors.Is(err, service.ErrEmailNotVerified):
+			helper.ForbiddenResponse(w, "Email address is not verified")
+		default:
+			helper.InternalServerError(w, "Failed to login", err)
+		}
+		return
+	}
+
+	if login.MFARequired {
+		helper.SuccessResponse(w, "MFA verification required", login)
+		return
+	}
+
+	helper.SuccessResponse(w, "Login successful", login)
+}
+
+func (a *AuthHandler) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	var payload dto.MFAVerifyReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid request payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateMFAVerifyReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Invalid request payload")
+		return
+	}
+
+	login, err := a.authService.VerifyMFA(r.Context(), &payload, clientInfo(r))
+	if err != nil {
+		var lockedErr *service.LoginLockedError
+		switch {
+		case errors.As(err, &lockedErr):
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+			helper.RateLimitExceededResponse(w, "Too many failed attempts, try again later")
+		case errors.Is(err, service.ErrInvalidToken):
+			helper.UnauthorizedResponse(w, "Invalid or expired MFA token")
+		case errors.Is(err, service.ErrInvalidMFACode):
+			helper.UnauthorizedResponse(w, "Invalid MFA code")
+		default:
+			helper.InternalServerError(w, "Failed to verify MFA code", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Login successful", login)
+}
+
+func (a *AuthHandler) OIDCAuthorize(w http.ResponseWriter, r *http.Request) {
+	provider := httprouter.ParamsFromContext(r.Context()).ByName("provider")
+
+	authorize, err := a.authService.OIDCAuthorize(r.Context(), provider)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownOIDCProvider):
+			helper.NotFoundResponse(w, "Unknown login provider")
+		default:
+			helper.InternalServerError(w, "Failed to start provider login", err)
+		}
+		return
+	}
+
+	http.SetCookie(w, oidcStateCookie(provider, authorize.State, authorize.ExpiresAt))
+	helper.SuccessResponse(w, "Redirect to the authorization url", authorize)
+}
+
+func (a *AuthHandler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	provider := httprouter.ParamsFromContext(r.Context()).ByName("provider")
+
+	var payload dto.OIDCCallbackReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid request payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateOIDCCallbackReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Invalid request payload")
+		return
+	}
+
+	// The state must come back from the browser that started the login, or a
+	// victim could be signed in to an attacker's account.
+	cookie, err := r.Cookie(oidcStateCookieName)
+	http.SetCookie(w, oidcStateCookie(provider, "", time.Time{}))
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(payload.State)) != 1 {
+		helper.UnauthorizedResponse(w, "Invalid or expired login state")
+		return
+	}
+
+	login, err := a.authService.OIDCLogin(r.Context(), provider, &payload, clientInfo(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownOIDCProvider):
+			helper.NotFoundResponse(w, "Unknown login provider")
+		case errors.Is(err, service.ErrInvalidToken):
+			helper.UnauthorizedResponse(w, "Invalid or expired login state")
+		case errors.Is(err, service.ErrOIDCLoginFailed):
+			helper.UnauthorizedResponse(w, "Login with provider failed")
+		case errors.Is(err, service.ErrOIDCEmailNotVerified):
+			helper.ForbiddenResponse(w, err.Error())
+		case errors.Is(err, service.ErrOIDCAccountConflict):
+			helper.EditConflictResponse(w, "Login with provider failed", err)
+		default:
+			helper.InternalServerError(w, "Failed to login with provider", err)
+		}
 		return
 	}
 
@@ -79,9 +202,14 @@ func (a *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	refreshToken, err := a.authService.RefreshToken(r.Context(), &payload)
+	refreshToken, err := a.authService.RefreshToken(r.Context(), &payload, clientInfo(r))
 	if err != nil {
-		helper.InternalServerError(w, "Failed to refresh token", err)
+		switch {
+		case errors.Is(err, service.ErrInvalidToken), errors.Is(err, service.ErrRefreshTokenReuse):
+			helper.UnauthorizedResponse(w, "Invalid or expired refresh token")
+		default:
+			helper.InternalServerError(w, "Failed to refresh token", err)
+		}
 		return
 	}
 
@@ -110,6 +238,134 @@ func (a *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	helper.SuccessResponse(w, "Logout successfully", nil)
 }
 
+func (a *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var payload dto.ForgotPasswordReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid request payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateForgotPassword(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Invalid request payload")
+		return
+	}
+
+	if err := a.authService.ForgotPassword(r.Context(), &payload); err != nil {
+		helper.InternalServerError(w, "Failed to request password reset", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "If the email is registered a password reset link has been sent", nil)
+}
+
+func (a *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var payload dto.ResetPasswordReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid request payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateResetPassword(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Invalid request payload")
+		return
+	}
+
+	if err := a.authService.ResetPassword(r.Context(), &payload); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidToken), errors.Is(err, utils.ErrPasswordTooLong):
+			helper.BadRequestResponse(w, "Failed to reset password", err)
+		default:
+			helper.InternalServerError(w, "Failed to reset password", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Password successfully reset", nil)
+}
+
+func (a *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var payload dto.VerifyEmailReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid request payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateVerifyEmail(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Invalid request payload")
+		return
+	}
+
+	if err := a.authService.VerifyEmail(r.Context(), &payload); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidToken):
+			helper.BadRequestResponse(w, "Failed to verify email", err)
+		default:
+			helper.InternalServerError(w, "Failed to verify email", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Email successfully verified", nil)
+}
+
+func (a *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var payload dto.ResendVerificationReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid request payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateResendVerification(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Invalid request payload")
+		return
+	}
+
+	if err := a.authService.ResendVerification(r.Context(), &payload); err != nil {
+		helper.InternalServerError(w, "Failed to resend verification email", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "If the email is registered and unverified a verification link has been sent", nil)
+}
+
+const oidcStateCookieName = "oidc_state"
+
+// oidcStateCookie binds state to the browser for the provider's callback. An
+// empty value deletes the cookie.
+func oidcStateCookie(provider, state string, expiresAt time.Time) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     oidcStateCookieName,
+		Value:    state,
+		Path:     "/v1/auth/oidc/" + provider + "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	if state == "" {
+		cookie.MaxAge = -1
+	} else {
+		cookie.Expires = expiresAt
+	}
+	return cookie
+}
+
+func clientInfo(r *http.Request) dto.ClientInfo {
+	ip, _ := utils.ClientIPFromCtx(r.Context())
+	return dto.ClientInfo{
+		IP:        ip,
+		UserAgent: r.UserAgent(),
+	}
+}
+
 func NewAuthHandler(authService service.AuthService) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/service"
+	"net/http"
+)
+
+type GenreHandler struct {
+	genreService service.GenreService
+}
+
+func (g *GenreHandler) CreateGenre(w http.ResponseWriter, r *http.Request) {
+	var payload dto.CreateGenreReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateCreateGenreReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	genre, err := g.genreService.CreateGenre(r.Context(), &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateGenre):
+			helper.EditConflictResponse(w, "Genre already exists", err)
+		default:
+			helper.InternalServerError(w, "Failed to create genre", err)
+		}
+		return
+	}
+
+	helper.CreatedResponse(w, "Genre successfully created", genre)
+}
+
+func (g *GenreHandler) GetGenre(w http.ResponseWriter, r *http.Request) {
+	genreId, err := helper.ReadIntParam(r, "genre_id")
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid genre_id", err)
+		return
+	}
+
+	genre, err := g.genreService.GetGenre(r.Context(), genreId)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Genre not found")
+		default:
+			helper.InternalServerError(w, "Failed to fetch genre", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Genre successfully fetched", genre)
+}
+
+func (g *GenreHandler) UpdateGenre(w http.ResponseWriter, r *http.Request) {
+	genreId, err := helper.ReadIntParam(r, "genre_id")
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid genre_id", err)
+		return
+	}
+
+	var payload dto.UpdateGenreReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateUpdateGenreReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	genre, err := g.genreService.UpdateGenre(r.Context(), genreId, &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Genre not found")
+		default:
+			helper.InternalServerError(w, "Failed to update genre", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Genre successfully updated", genre)
+}
+
+func (g *GenreHandler) DeleteGenre(w http.ResponseWriter, r *http.Request) {
+	genreId, err := helper.ReadIntParam(r, "genre_id")
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid genre_id", err)
+		return
+	}
+
+	reassignTo, err := helper.ReadOptionalIntQuery(r, "reassign_to")
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid reassign_to", err)
+		return
+	}
+
+	if err := g.genreService.DeleteGenre(r.Context(), genreId, reassignTo); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Genre not found")
+		case errors.Is(err, service.ErrGenreInUse):
+			helper.EditConflictResponse(w, "Genre is in use, provide reassign_to to move references", err)
+		case errors.Is(err, service.ErrInvalidReassignTarget):
+			helper.BadRequestResponse(w, "Invalid reassign_to", err)
+		default:
+			helper.InternalServerError(w, "Failed to delete genre", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Genre successfully deleted", nil)
+}
+
+func NewGenreHandler(genreService service.GenreService) *GenreHandler {
+	return &GenreHandler{
+		genreService: genreService,
+	}
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"net/http"
+)
+
+type JWKSHandler struct {
+	keys *utils.KeySet
+}
+
+func (j *JWKSHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	helper.JSONResponse(w, j.keys.JWKS())
+}
+
+func NewJWKSHandler(keys *utils.KeySet) *JWKSHandler {
+	return &JWKSHandler{
+		keys: keys,
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/service"
+	"net/http"
+)
+
+type MFAHandler struct {
+	mfaService service.MFAService
+}
+
+func (m *MFAHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if !isCaller(r, id) {
+		helper.ForbiddenResponse(w, "You can only manage MFA for your own account")
+		return
+	}
+
+	enrollment, err := m.mfaService.Enroll(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Failed to fetch a user")
+		case errors.Is(err, service.ErrMFAAlreadyEnabled):
+			helper.EditConflictResponse(w, "Failed to start MFA enrollment", err)
+		default:
+			helper.InternalServerError(w, "Failed to start MFA enrollment", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Scan the otpauth URI and confirm with a code", enrollment)
+}
+
+func (m *MFAHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if !isCaller(r, id) {
+		helper.ForbiddenResponse(w, "You can only manage MFA for your own account")
+		return
+	}
+
+	var payload dto.MFACodeReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "invalid payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateMFACodeReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	codes, err := m.mfaService.Confirm(r.Context(), id, &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Failed to fetch a user")
+		case errors.Is(err, service.ErrMFAAlreadyEnabled):
+			helper.EditConflictResponse(w, "Failed to confirm MFA", err)
+		case errors.Is(err, service.ErrMFANotEnrolled), errors.Is(err, service.ErrInvalidMFACode):
+			helper.BadRequestResponse(w, "Failed to confirm MFA", err)
+		default:
+			helper.InternalServerError(w, "Failed to confirm MFA", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "MFA enabled, store these recovery codes somewhere safe", codes)
+}
+
+func (m *MFAHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if !isCaller(r, id) {
+		helper.ForbiddenResponse(w, "You can only manage MFA for your own account")
+		return
+	}
+
+	var payload dto.MFACodeReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "invalid payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateMFACodeReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	if err := m.mfaService.Disable(r.Context(), id, &payload); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Failed to fetch a user")
+		case errors.Is(err, service.ErrMFANotEnabled), errors.Is(err, service.ErrInvalidMFACode):
+			helper.BadRequestResponse(w, "Failed to disable MFA", err)
+		default:
+			helper.InternalServerError(w, "Failed to disable MFA", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "MFA disabled", nil)
+}
+
+func NewMFAHandler(mfaService service.MFAService) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+	}
+}
@@ -3,7 +3,6 @@ package handlers
 import (
 	"errors"
 	"github.com/julienschmidt/httprouter"
-	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
@@ -11,6 +10,7 @@ import (
 	"github.com/saleh-ghazimoradi/Projectopher/utils"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 type MovieHandler struct {
@@ -33,7 +33,14 @@ func (m *MovieHandler) AddMovie(w http.ResponseWriter, r *http.Request) {
 
 	movie, err := m.movieService.CreateMovie(r.Context(), &payload)
 	if err != nil {
-		helper.InternalServerError(w, "failed to add movie", err)
+		switch {
+		case errors.Is(err, repository.ErrDuplicateImdb):
+			helper.EditConflictResponse(w, "Movie already exists", err)
+		case errors.Is(err, service.ErrUnknownRanking):
+			helper.BadRequestResponse(w, "Invalid ranking", err)
+		default:
+			helper.InternalServerError(w, "failed to add movie", err)
+		}
 		return
 	}
 
@@ -58,6 +65,7 @@ func (m *MovieHandler) GetMovie(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	helper.SetETag(w, movie.Version)
 	helper.SuccessResponse(w, "Movie successfully fetched", movie)
 }
 
@@ -72,7 +80,68 @@ func (m *MovieHandler) GetMovies(w http.ResponseWriter, r *http.Request) {
 		limit = 10
 	}
 
-	movies, meta, err := m.movieService.GetMovies(r.Context(), page, limit)
+	query := dto.ListMoviesReq{
+		Page:  page,
+		Limit: limit,
+		Q:     strings.TrimSpace(r.URL.Query().Get("q")),
+		Sort:  r.URL.Query().Get("sort"),
+	}
+
+	var err error
+	if query.Genre, err = helper.ReadOptionalIntQuery(r, "genre"); err != nil {
+		helper.BadRequestResponse(w, "Invalid genre", err)
+		return
+	}
+
+	if query.Ranking, err = helper.ReadOptionalIntQuery(r, "ranking"); err != nil {
+		helper.BadRequestResponse(w, "Invalid ranking", err)
+		return
+	}
+
+	if query.CreatedAfter, err = helper.ReadOptionalTimeQuery(r, "created_after"); err != nil {
+		helper.BadRequestResponse(w, "Invalid created_after", err)
+		return
+	}
+
+	if query.CreatedBefore, err = helper.ReadOptionalTimeQuery(r, "created_before"); err != nil {
+		helper.BadRequestResponse(w, "Invalid created_before", err)
+		return
+	}
+
+	if r.URL.Query().Has("cursor") {
+		cursor := r.URL.Query().Get("cursor")
+		query.Cursor = &cursor
+	}
+
+	if query.IncludeTotal, err = helper.ReadBoolQuery(r, "include_total"); err != nil {
+		helper.BadRequestResponse(w, "Invalid include_total", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateListMoviesReq(v, &query)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Invalid query parameters")
+		return
+	}
+
+	if query.Cursor != nil {
+		movies, meta, err := m.movieService.GetMoviesByCursor(r.Context(), &query)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrInvalidCursor):
+				helper.BadRequestResponse(w, "Invalid cursor", err)
+			default:
+				helper.InternalServerError(w, "Failed to fetch movies", err)
+			}
+			return
+		}
+
+		helper.CursorPaginatedSuccessResponse(w, "Movies successfully retrieved", movies, *meta)
+		return
+	}
+
+	movies, meta, err := m.movieService.GetMovies(r.Context(), &query)
 	if err != nil {
 		switch {
 		case errors.Is(err, repository.ErrRecordNotFound):
@@ -86,21 +155,126 @@ func (m *MovieHandler) GetMovies(w http.ResponseWriter, r *http.Request) {
 	helper.PaginatedSuccessResponse(w, "Movies successfully retrieved", movies, *meta)
 }
 
-func (m *MovieHandler) AdminReviewUpdate(w http.ResponseWriter, r *http.Request) {
-	role, exists := utils.RoleFromCtx(r.Context())
-	if !exists {
-		helper.BadRequestResponse(w, "Invalid role", nil)
+func (m *MovieHandler) UpdateMovie(w http.ResponseWriter, r *http.Request) {
+	imdbId := httprouter.ParamsFromContext(r.Context()).ByName("imdb_id")
+	if imdbId == "" {
+		helper.BadRequestResponse(w, "Invalid imdb_id", errors.New("imdb_id is required"))
+		return
+	}
+
+	var payload dto.UpdateMovieReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	if payload.Version == nil {
+		version, err := helper.ReadIfMatch(r)
+		if err != nil {
+			helper.BadRequestResponse(w, "Invalid If-Match header", err)
+			return
+		}
+		payload.Version = version
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateUpdateMovieReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	movie, err := m.movieService.UpdateMovie(r.Context(), imdbId, &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Movie not found")
+		case errors.Is(err, repository.ErrEditConflict):
+			helper.EditConflictResponse(w, "Movie was modified by another request", err)
+		case errors.Is(err, service.ErrUnknownRanking):
+			helper.BadRequestResponse(w, "Invalid ranking", err)
+		default:
+			helper.InternalServerError(w, "Failed to update movie", err)
+		}
+		return
+	}
+
+	helper.SetETag(w, movie.Version)
+	helper.SuccessResponse(w, "Movie successfully updated", movie)
+}
+
+func (m *MovieHandler) ReplaceMovie(w http.ResponseWriter, r *http.Request) {
+	imdbId := httprouter.ParamsFromContext(r.Context()).ByName("imdb_id")
+	if imdbId == "" {
+		helper.BadRequestResponse(w, "Invalid imdb_id", errors.New("imdb_id is required"))
+		return
+	}
+
+	var payload dto.ReplaceMovieReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
 		return
 	}
 
-	if role != string(domain.UserRoleAdmin) {
-		helper.UnauthorizedResponse(w, "User must be part of the admin role")
+	if payload.Version == nil {
+		version, err := helper.ReadIfMatch(r)
+		if err != nil {
+			helper.BadRequestResponse(w, "Invalid If-Match header", err)
+			return
+		}
+		payload.Version = version
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateReplaceMovieReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
 		return
 	}
 
-	imdbId := r.URL.Query().Get("imdb_id")
+	movie, err := m.movieService.ReplaceMovie(r.Context(), imdbId, &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Movie not found")
+		case errors.Is(err, repository.ErrEditConflict):
+			helper.EditConflictResponse(w, "Movie was modified by another request", err)
+		case errors.Is(err, service.ErrUnknownRanking):
+			helper.BadRequestResponse(w, "Invalid ranking", err)
+		default:
+			helper.InternalServerError(w, "Failed to replace movie", err)
+		}
+		return
+	}
+
+	helper.SetETag(w, movie.Version)
+	helper.SuccessResponse(w, "Movie successfully replaced", movie)
+}
+
+func (m *MovieHandler) DeleteMovie(w http.ResponseWriter, r *http.Request) {
+	imdbId := httprouter.ParamsFromContext(r.Context()).ByName("imdb_id")
 	if imdbId == "" {
-		helper.BadRequestResponse(w, "Invalid imdb_id", nil)
+		helper.BadRequestResponse(w, "Invalid imdb_id", errors.New("imdb_id is required"))
+		return
+	}
+
+	if err := m.movieService.DeleteMovie(r.Context(), imdbId); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Movie not found")
+		default:
+			helper.InternalServerError(w, "Failed to delete movie", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Movie successfully deleted", nil)
+}
+
+func (m *MovieHandler) AdminReviewUpdate(w http.ResponseWriter, r *http.Request) {
+	imdbId := httprouter.ParamsFromContext(r.Context()).ByName("imdb_id")
+	if imdbId == "" {
+		helper.BadRequestResponse(w, "Invalid imdb_id", errors.New("imdb_id is required"))
 		return
 	}
 
@@ -110,6 +284,15 @@ func (m *MovieHandler) AdminReviewUpdate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if payload.Version == nil {
+		version, err := helper.ReadIfMatch(r)
+		if err != nil {
+			helper.BadRequestResponse(w, "Invalid If-Match header", err)
+			return
+		}
+		payload.Version = version
+	}
+
 	v := helper.NewValidator()
 	dto.ValidateAdminReview(v, &payload)
 	if !v.Valid() {
@@ -119,10 +302,18 @@ func (m *MovieHandler) AdminReviewUpdate(w http.ResponseWriter, r *http.Request)
 
 	resp, err := m.movieService.UpdateAdminReview(r.Context(), imdbId, &payload)
 	if err != nil {
-		helper.InternalServerError(w, "Failed to update admin review", err)
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Movie not found")
+		case errors.Is(err, repository.ErrEditConflict):
+			helper.EditConflictResponse(w, "Movie was modified by another request", err)
+		default:
+			helper.InternalServerError(w, "Failed to update admin review", err)
+		}
 		return
 	}
 
+	helper.SetETag(w, resp.Version)
 	helper.SuccessResponse(w, "Admin review successfully updated", resp)
 }
 
@@ -146,6 +337,7 @@ func (m *MovieHandler) GetGenres(w http.ResponseWriter, r *http.Request) {
 	genres, err := m.movieService.GetGenres(r.Context())
 	if err != nil {
 		helper.InternalServerError(w, "Failed to fetch genres", err)
+		return
 	}
 	helper.SuccessResponse(w, "Genres successfully retrieved", genres)
 }
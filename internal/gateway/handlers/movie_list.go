@@ -0,0 +1,376 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/authz"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/service"
+	"net/http"
+	"strconv"
+)
+
+type MovieListHandler struct {
+	movieListService service.MovieListService
+}
+
+func (mh *MovieListHandler) CreateMovieList(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if !authz.Can(r.Context(), authz.ActionCreate, authz.MovieList(id)) {
+		helper.ForbiddenResponse(w, "You can only create your own lists")
+		return
+	}
+
+	var payload dto.CreateMovieListReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "invalid payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateCreateMovieListReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	list, err := mh.movieListService.CreateMovieList(r.Context(), id, &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownMovie):
+			helper.NotFoundResponse(w, "Movie not found")
+		default:
+			helper.InternalServerError(w, "Failed to create list", err)
+		}
+		return
+	}
+
+	helper.SetETag(w, list.Version)
+	helper.CreatedResponse(w, "List successfully created", list)
+}
+
+func (mh *MovieListHandler) GetPublicMovieLists(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.ParseInt(r.URL.Query().Get("page"), 10, 64)
+	if page < 0 {
+		page = 1
+	}
+
+	limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+	if limit < 0 {
+		limit = 10
+	}
+
+	query := dto.ListMovieListsReq{
+		Page:  page,
+		Limit: limit,
+	}
+
+	if r.URL.Query().Has("cursor") {
+		cursor := r.URL.Query().Get("cursor")
+		query.Cursor = &cursor
+	}
+
+	var err error
+	if query.IncludeTotal, err = helper.ReadBoolQuery(r, "include_total"); err != nil {
+		helper.BadRequestResponse(w, "Invalid include_total", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateListMovieListsReq(v, &query)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Invalid query parameters")
+		return
+	}
+
+	if query.Cursor != nil {
+		lists, meta, err := mh.movieListService.GetPublicMovieListsByCursor(r.Context(), &query)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrInvalidCursor):
+				helper.BadRequestResponse(w, "Invalid cursor", err)
+			default:
+				helper.InternalServerError(w, "Failed to fetch lists", err)
+			}
+			return
+		}
+
+		helper.CursorPaginatedSuccessResponse(w, "Lists successfully retrieved", lists, *meta)
+		return
+	}
+
+	lists, meta, err := mh.movieListService.GetPublicMovieLists(r.Context(), &query)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to fetch lists", err)
+		return
+	}
+
+	helper.PaginatedSuccessResponse(w, "Lists successfully retrieved", lists, *meta)
+}
+
+func (mh *MovieListHandler) GetSharedMovieList(w http.ResponseWriter, r *http.Request) {
+	slug := httprouter.ParamsFromContext(r.Context()).ByName("slug")
+	list, err := mh.movieListService.GetSharedMovieList(r.Context(), slug)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "List not found")
+		default:
+			helper.InternalServerError(w, "Failed to fetch list", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "List successfully retrieved", list)
+}
+
+// GetUserMovieLists shows other users only the public lists of id.
+func (mh *MovieListHandler) GetUserMovieLists(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	publicOnly := !authz.Can(r.Context(), authz.ActionRead, authz.MovieList(id))
+	lists, err := mh.movieListService.GetUserMovieLists(r.Context(), id, publicOnly)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to fetch lists", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Lists successfully retrieved", lists)
+}
+
+func (mh *MovieListHandler) GetMovieList(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	listId := httprouter.ParamsFromContext(r.Context()).ByName("list_id")
+	list, err := mh.movieListService.GetMovieList(r.Context(), id, listId)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "List not found")
+		default:
+			helper.InternalServerError(w, "Failed to fetch list", err)
+		}
+		return
+	}
+
+	if list.Visibility != string(domain.ListVisibilityPublic) && !authz.Can(r.Context(), authz.ActionRead, authz.MovieList(id)) {
+		helper.NotFoundResponse(w, "List not found")
+		return
+	}
+
+	helper.SetETag(w, list.Version)
+	helper.SuccessResponse(w, "List successfully retrieved", list)
+}
+
+func (mh *MovieListHandler) UpdateMovieList(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if !authz.Can(r.Context(), authz.ActionUpdate, authz.MovieList(id)) {
+		helper.ForbiddenResponse(w, "You can only change your own lists")
+		return
+	}
+
+	var payload dto.UpdateMovieListReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "invalid payload", err)
+		return
+	}
+
+	if payload.Version == nil {
+		version, err := helper.ReadIfMatch(r)
+		if err != nil {
+			helper.BadRequestResponse(w, "Invalid If-Match header", err)
+			return
+		}
+		payload.Version = version
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateUpdateMovieListReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	listId := httprouter.ParamsFromContext(r.Context()).ByName("list_id")
+	list, err := mh.movieListService.UpdateMovieList(r.Context(), id, listId, &payload)
+	if err != nil {
+		mh.writeError(w, err, "Failed to update list")
+		return
+	}
+
+	helper.SetETag(w, list.Version)
+	helper.SuccessResponse(w, "List successfully updated", list)
+}
+
+func (mh *MovieListHandler) DeleteMovieList(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if !authz.Can(r.Context(), authz.ActionDelete, authz.MovieList(id)) {
+		helper.ForbiddenResponse(w, "You are not authorized to delete this list")
+		return
+	}
+
+	listId := httprouter.ParamsFromContext(r.Context()).ByName("list_id")
+	if err := mh.movieListService.DeleteMovieList(r.Context(), id, listId); err != nil {
+		mh.writeError(w, err, "Failed to delete list")
+		return
+	}
+
+	helper.SuccessResponse(w, "List successfully deleted", nil)
+}
+
+func (mh *MovieListHandler) AddMovieListItem(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if !authz.Can(r.Context(), authz.ActionUpdate, authz.MovieList(id)) {
+		helper.ForbiddenResponse(w, "You can only change your own lists")
+		return
+	}
+
+	var payload dto.AddMovieListItemReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "invalid payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateAddMovieListItemReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	listId := httprouter.ParamsFromContext(r.Context()).ByName("list_id")
+	list, err := mh.movieListService.AddMovieListItem(r.Context(), id, listId, &payload)
+	if err != nil {
+		mh.writeError(w, err, "Failed to add movie to list")
+		return
+	}
+
+	helper.SetETag(w, list.Version)
+	helper.CreatedResponse(w, "Movie successfully added to list", list)
+}
+
+func (mh *MovieListHandler) RemoveMovieListItem(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if !authz.Can(r.Context(), authz.ActionUpdate, authz.MovieList(id)) {
+		helper.ForbiddenResponse(w, "You can only change your own lists")
+		return
+	}
+
+	params := httprouter.ParamsFromContext(r.Context())
+	list, err := mh.movieListService.RemoveMovieListItem(r.Context(), id, params.ByName("list_id"), params.ByName("imdb_id"))
+	if err != nil {
+		mh.writeError(w, err, "Failed to remove movie from list")
+		return
+	}
+
+	helper.SetETag(w, list.Version)
+	helper.SuccessResponse(w, "Movie successfully removed from list", list)
+}
+
+func (mh *MovieListHandler) ReorderMovieList(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if !authz.Can(r.Context(), authz.ActionUpdate, authz.MovieList(id)) {
+		helper.ForbiddenResponse(w, "You can only change your own lists")
+		return
+	}
+
+	var payload dto.ReorderMovieListReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "invalid payload", err)
+		return
+	}
+
+	if payload.Version == nil {
+		version, err := helper.ReadIfMatch(r)
+		if err != nil {
+			helper.BadRequestResponse(w, "Invalid If-Match header", err)
+			return
+		}
+		payload.Version = version
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateReorderMovieListReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	listId := httprouter.ParamsFromContext(r.Context()).ByName("list_id")
+	list, err := mh.movieListService.ReorderMovieList(r.Context(), id, listId, &payload)
+	if err != nil {
+		mh.writeError(w, err, "Failed to reorder list")
+		return
+	}
+
+	helper.SetETag(w, list.Version)
+	helper.SuccessResponse(w, "List successfully reordered", list)
+}
+
+func (mh *MovieListHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, repository.ErrRecordNotFound):
+		helper.NotFoundResponse(w, "List not found")
+	case errors.Is(err, repository.ErrEditConflict):
+		helper.EditConflictResponse(w, "List was modified by another request", err)
+	case errors.Is(err, service.ErrUnknownMovie):
+		helper.NotFoundResponse(w, "Movie not found")
+	case errors.Is(err, service.ErrMovieNotListed):
+		helper.NotFoundResponse(w, "Movie is not on the list")
+	case errors.Is(err, service.ErrMovieAlreadyListed):
+		helper.EditConflictResponse(w, "Movie is already on the list", err)
+	case errors.Is(err, service.ErrMovieListFull):
+		helper.FailedValidationResponse(w, "List already holds the maximum number of movies")
+	case errors.Is(err, service.ErrInvalidListOrder):
+		helper.FailedValidationResponse(w, "Order must name every movie on the list exactly once")
+	default:
+		helper.InternalServerError(w, message, err)
+	}
+}
+
+func NewMovieListHandler(movieListService service.MovieListService) *MovieListHandler {
+	return &MovieListHandler{
+		movieListService: movieListService,
+	}
+}
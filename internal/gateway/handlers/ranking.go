@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/service"
+	"net/http"
+)
+
+type RankingHandler struct {
+	rankingService service.RankingService
+}
+
+func (rh *RankingHandler) CreateRanking(w http.ResponseWriter, r *http.Request) {
+	var payload dto.CreateRankingReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateCreateRankingReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	ranking, err := rh.rankingService.CreateRanking(r.Context(), &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateRanking):
+			helper.EditConflictResponse(w, "Ranking already exists", err)
+		default:
+			helper.InternalServerError(w, "Failed to create ranking", err)
+		}
+		return
+	}
+
+	helper.CreatedResponse(w, "Ranking successfully created", ranking)
+}
+
+func (rh *RankingHandler) GetRanking(w http.ResponseWriter, r *http.Request) {
+	rankingValue, err := helper.ReadIntParam(r, "ranking_value")
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid ranking_value", err)
+		return
+	}
+
+	ranking, err := rh.rankingService.GetRanking(r.Context(), rankingValue)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Ranking not found")
+		default:
+			helper.InternalServerError(w, "Failed to fetch ranking", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Ranking successfully fetched", ranking)
+}
+
+func (rh *RankingHandler) GetRankings(w http.ResponseWriter, r *http.Request) {
+	rankings, err := rh.rankingService.GetRankings(r.Context())
+	if err != nil {
+		helper.InternalServerError(w, "Failed to fetch rankings", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Rankings successfully retrieved", rankings)
+}
+
+func (rh *RankingHandler) UpdateRanking(w http.ResponseWriter, r *http.Request) {
+	rankingValue, err := helper.ReadIntParam(r, "ranking_value")
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid ranking_value", err)
+		return
+	}
+
+	var payload dto.UpdateRankingReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateUpdateRankingReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	ranking, err := rh.rankingService.UpdateRanking(r.Context(), rankingValue, &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Ranking not found")
+		default:
+			helper.InternalServerError(w, "Failed to update ranking", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Ranking successfully updated", ranking)
+}
+
+func (rh *RankingHandler) DeleteRanking(w http.ResponseWriter, r *http.Request) {
+	rankingValue, err := helper.ReadIntParam(r, "ranking_value")
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid ranking_value", err)
+		return
+	}
+
+	reassignTo, err := helper.ReadOptionalIntQuery(r, "reassign_to")
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid reassign_to", err)
+		return
+	}
+
+	if err := rh.rankingService.DeleteRanking(r.Context(), rankingValue, reassignTo); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Ranking not found")
+		case errors.Is(err, service.ErrRankingInUse):
+			helper.EditConflictResponse(w, "Ranking is in use, provide reassign_to to move references", err)
+		case errors.Is(err, service.ErrInvalidReassignTarget):
+			helper.BadRequestResponse(w, "Invalid reassign_to", err)
+		default:
+			helper.InternalServerError(w, "Failed to delete ranking", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Ranking successfully deleted", nil)
+}
+
+func NewRankingHandler(rankingService service.RankingService) *RankingHandler {
+	return &RankingHandler{
+		rankingService: rankingService,
+	}
+}
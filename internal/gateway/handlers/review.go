@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/authz"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/service"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"net/http"
+	"strconv"
+)
+
+type ReviewHandler struct {
+	reviewService service.ReviewService
+}
+
+func (rh *ReviewHandler) CreateReview(w http.ResponseWriter, r *http.Request) {
+	imdbId := httprouter.ParamsFromContext(r.Context()).ByName("imdb_id")
+	if imdbId == "" {
+		helper.BadRequestResponse(w, "Invalid imdb_id", errors.New("imdb_id is required"))
+		return
+	}
+
+	if !authz.Can(r.Context(), authz.ActionCreate, authz.Reviews()) {
+		helper.ForbiddenResponse(w, "You are not authorized to review movies")
+		return
+	}
+
+	var payload dto.CreateReviewReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "invalid payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateCreateReviewReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	userId, _ := utils.UserIdFromCtx(r.Context())
+	review, err := rh.reviewService.CreateReview(r.Context(), imdbId, userId, &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Movie not found")
+		case errors.Is(err, repository.ErrDuplicateReview):
+			helper.EditConflictResponse(w, "You have already reviewed this movie", err)
+		default:
+			helper.InternalServerError(w, "Failed to create review", err)
+		}
+		return
+	}
+
+	helper.CreatedResponse(w, "Review successfully created", review)
+}
+
+func (rh *ReviewHandler) GetReviews(w http.ResponseWriter, r *http.Request) {
+	imdbId := httprouter.ParamsFromContext(r.Context()).ByName("imdb_id")
+	if imdbId == "" {
+		helper.BadRequestResponse(w, "Invalid imdb_id", errors.New("imdb_id is required"))
+		return
+	}
+
+	limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+	if limit < 0 {
+		limit = 10
+	}
+
+	includeTotal, err := helper.ReadBoolQuery(r, "include_total")
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid include_total", err)
+		return
+	}
+
+	reviews, meta, err := rh.reviewService.GetReviews(r.Context(), imdbId, r.URL.Query().Get("cursor"), limit, includeTotal)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Movie not found")
+		case errors.Is(err, service.ErrInvalidCursor):
+			helper.BadRequestResponse(w, "Invalid cursor", err)
+		default:
+			helper.InternalServerError(w, "Failed to fetch reviews", err)
+		}
+		return
+	}
+
+	helper.CursorPaginatedSuccessResponse(w, "Reviews successfully retrieved", reviews, *meta)
+}
+
+func (rh *ReviewHandler) UpdateReview(w http.ResponseWriter, r *http.Request) {
+	imdbId := httprouter.ParamsFromContext(r.Context()).ByName("imdb_id")
+	if imdbId == "" {
+		helper.BadRequestResponse(w, "Invalid imdb_id", errors.New("imdb_id is required"))
+		return
+	}
+
+	userId, _ := utils.UserIdFromCtx(r.Context())
+	if !authz.Can(r.Context(), authz.ActionUpdate, authz.Review(userId)) {
+		helper.ForbiddenResponse(w, "You are not authorized to edit reviews")
+		return
+	}
+
+	var payload dto.UpdateReviewReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "invalid payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateUpdateReviewReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	review, err := rh.reviewService.UpdateReview(r.Context(), imdbId, userId, &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Review not found")
+		default:
+			helper.InternalServerError(w, "Failed to update review", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Review successfully updated", review)
+}
+
+// DeleteReview removes the caller's review, or with ?user_id= another user's
+// review when the caller is allowed to moderate it.
+func (rh *ReviewHandler) DeleteReview(w http.ResponseWriter, r *http.Request) {
+	imdbId := httprouter.ParamsFromContext(r.Context()).ByName("imdb_id")
+	if imdbId == "" {
+		helper.BadRequestResponse(w, "Invalid imdb_id", errors.New("imdb_id is required"))
+		return
+	}
+
+	userId := r.URL.Query().Get("user_id")
+	if userId == "" {
+		userId, _ = utils.UserIdFromCtx(r.Context())
+	}
+
+	if !authz.Can(r.Context(), authz.ActionDelete, authz.Review(userId)) {
+		helper.ForbiddenResponse(w, "You are not authorized to delete this review")
+		return
+	}
+
+	if err := rh.reviewService.DeleteReview(r.Context(), imdbId, userId); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Review not found")
+		default:
+			helper.InternalServerError(w, "Failed to delete review", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Review successfully deleted", nil)
+}
+
+func NewReviewHandler(reviewService service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
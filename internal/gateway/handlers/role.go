@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/service"
+	"net/http"
+)
+
+type RoleHandler struct {
+	roleService service.RoleService
+}
+
+func (rh *RoleHandler) GetRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := rh.roleService.GetRoles(r.Context())
+	if err != nil {
+		helper.InternalServerError(w, "Failed to fetch roles", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Roles successfully retrieved", roles)
+}
+
+func (rh *RoleHandler) UpdateRolePermissions(w http.ResponseWriter, r *http.Request) {
+	name := httprouter.ParamsFromContext(r.Context()).ByName("name")
+	if name == "" {
+		helper.BadRequestResponse(w, "Invalid name", errors.New("name is required"))
+		return
+	}
+
+	var payload dto.UpdateRolePermissionsReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateUpdateRolePermissionsReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	role, err := rh.roleService.UpdateRolePermissions(r.Context(), name, &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Role not found")
+		default:
+			helper.InternalServerError(w, "Failed to update role", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Role successfully updated", role)
+}
+
+func NewRoleHandler(roleService service.RoleService) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+	}
+}
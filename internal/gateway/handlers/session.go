@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/authz"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/service"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"net/http"
+)
+
+type SessionHandler struct {
+	sessionService service.SessionService
+}
+
+func (s *SessionHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if !authz.Can(r.Context(), authz.ActionRead, authz.User(id)) {
+		helper.ForbiddenResponse(w, "You are not authorized to access this resource")
+		return
+	}
+
+	sessions, err := s.sessionService.GetSessions(r.Context(), id)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to fetch sessions", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Sessions successfully retrieved", sessions)
+}
+
+func (s *SessionHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	sessionId := httprouter.ParamsFromContext(r.Context()).ByName("session_id")
+	if id == "" || sessionId == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id and session id are required"))
+		return
+	}
+
+	if !authz.Can(r.Context(), authz.ActionDelete, authz.User(id)) {
+		helper.ForbiddenResponse(w, "You are not authorized to access this resource")
+		return
+	}
+
+	if err := s.sessionService.RevokeSession(r.Context(), id, sessionId); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Session not found")
+		default:
+			helper.InternalServerError(w, "Failed to revoke session", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Session successfully revoked", nil)
+}
+
+func (s *SessionHandler) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if err := s.sessionService.RevokeAllSessions(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Failed to fetch a user")
+		default:
+			helper.InternalServerError(w, "Failed to revoke sessions", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "All sessions successfully revoked", nil)
+}
+
+func (s *SessionHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	id, _ := utils.UserIdFromCtx(r.Context())
+
+	if err := s.sessionService.RevokeAllSessions(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Failed to fetch a user")
+		default:
+			helper.InternalServerError(w, "Failed to logout", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Logged out of all sessions", nil)
+}
+
+func NewSessionHandler(sessionService service.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
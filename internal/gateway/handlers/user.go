@@ -3,10 +3,12 @@ package handlers
 import (
 	"errors"
 	"github.com/julienschmidt/httprouter"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/authz"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/service"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
 	"net/http"
 	"strconv"
 )
@@ -16,12 +18,17 @@ type UserHandler struct {
 }
 
 func (u *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
-	id := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	id := userIdParam(r)
 	if id == "" {
 		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
 		return
 	}
 
+	if !authz.Can(r.Context(), authz.ActionRead, authz.User(id)) {
+		helper.ForbiddenResponse(w, "You are not authorized to access this resource")
+		return
+	}
+
 	profile, err := u.userService.GetProfile(r.Context(), id)
 	if err != nil {
 		switch {
@@ -33,10 +40,16 @@ func (u *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	helper.SetETag(w, profile.Version)
 	helper.SuccessResponse(w, "profile successfully retrieved", profile)
 }
 
 func (u *UserHandler) GetProfiles(w http.ResponseWriter, r *http.Request) {
+	if !authz.Can(r.Context(), authz.ActionList, authz.Users()) {
+		helper.ForbiddenResponse(w, "You are not authorized to access this resource")
+		return
+	}
+
 	page, _ := strconv.ParseInt(r.URL.Query().Get("page"), 10, 64)
 	if page < 0 {
 		page = 1
@@ -47,6 +60,28 @@ func (u *UserHandler) GetProfiles(w http.ResponseWriter, r *http.Request) {
 		limit = 10
 	}
 
+	if r.URL.Query().Has("cursor") {
+		includeTotal, err := helper.ReadBoolQuery(r, "include_total")
+		if err != nil {
+			helper.BadRequestResponse(w, "Invalid include_total", err)
+			return
+		}
+
+		users, meta, err := u.userService.GetProfilesByCursor(r.Context(), r.URL.Query().Get("cursor"), limit, includeTotal)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrInvalidCursor):
+				helper.BadRequestResponse(w, "Invalid cursor", err)
+			default:
+				helper.InternalServerError(w, "Failed to fetch users", err)
+			}
+			return
+		}
+
+		helper.CursorPaginatedSuccessResponse(w, "Users successfully retrieved", users, *meta)
+		return
+	}
+
 	users, meta, err := u.userService.GetProfiles(r.Context(), page, limit)
 	if err != nil {
 		switch {
@@ -62,18 +97,32 @@ func (u *UserHandler) GetProfiles(w http.ResponseWriter, r *http.Request) {
 }
 
 func (u *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
-	id := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	id := userIdParam(r)
 	if id == "" {
 		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
 		return
 	}
 
+	if !authz.Can(r.Context(), authz.ActionUpdate, authz.User(id)) {
+		helper.ForbiddenResponse(w, "You are not authorized to access this resource")
+		return
+	}
+
 	var payload dto.UpdateUserReq
 	if err := helper.ReadJSON(w, r, &payload); err != nil {
 		helper.BadRequestResponse(w, "invalid payload", err)
 		return
 	}
 
+	if payload.Version == nil {
+		version, err := helper.ReadIfMatch(r)
+		if err != nil {
+			helper.BadRequestResponse(w, "Invalid If-Match header", err)
+			return
+		}
+		payload.Version = version
+	}
+
 	v := helper.NewValidator()
 	dto.ValidateUserUpdateReq(v, &payload)
 	if !v.Valid() {
@@ -86,21 +135,29 @@ func (u *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, repository.ErrRecordNotFound):
 			helper.NotFoundResponse(w, "Failed to fetch a user")
+		case errors.Is(err, repository.ErrEditConflict):
+			helper.EditConflictResponse(w, "User was modified by another request", err)
 		default:
 			helper.InternalServerError(w, "Failed to fetch a user", err)
 		}
 		return
 	}
 
+	helper.SetETag(w, updatedUser.Version)
 	helper.SuccessResponse(w, "User successfully updated", updatedUser)
 }
 
 func (u *UserHandler) DeleteProfile(w http.ResponseWriter, r *http.Request) {
-	id := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	id := userIdParam(r)
 	if id == "" {
 		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
 		return
 	}
+
+	if !authz.Can(r.Context(), authz.ActionDelete, authz.User(id)) {
+		helper.ForbiddenResponse(w, "You are not authorized to access this resource")
+		return
+	}
 	if err := u.userService.DeleteProfile(r.Context(), id); err != nil {
 		switch {
 		case errors.Is(err, repository.ErrRecordNotFound):
@@ -114,6 +171,158 @@ func (u *UserHandler) DeleteProfile(w http.ResponseWriter, r *http.Request) {
 	helper.SuccessResponse(w, "User successfully deleted", nil)
 }
 
+func (u *UserHandler) AssignRole(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	var payload dto.AssignRoleReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "invalid payload", err)
+		return
+	}
+
+	if payload.Version == nil {
+		version, err := helper.ReadIfMatch(r)
+		if err != nil {
+			helper.BadRequestResponse(w, "Invalid If-Match header", err)
+			return
+		}
+		payload.Version = version
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateAssignRoleReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	updatedUser, err := u.userService.AssignRole(r.Context(), id, &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Failed to fetch a user")
+		case errors.Is(err, service.ErrUnknownRole):
+			helper.BadRequestResponse(w, "Invalid role", err)
+		case errors.Is(err, repository.ErrEditConflict):
+			helper.EditConflictResponse(w, "User was modified by another request", err)
+		default:
+			helper.InternalServerError(w, "Failed to assign role", err)
+		}
+		return
+	}
+
+	helper.SetETag(w, updatedUser.Version)
+	helper.SuccessResponse(w, "Role successfully assigned", updatedUser)
+}
+
+func (u *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if !isCaller(r, id) {
+		helper.ForbiddenResponse(w, "You can only change your own password")
+		return
+	}
+
+	var payload dto.ChangePasswordReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "invalid payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateChangePasswordReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	if err := u.userService.ChangePassword(r.Context(), id, &payload); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Failed to fetch a user")
+		case errors.Is(err, service.ErrIncorrectPassword):
+			helper.BadRequestResponse(w, "Failed to change password", err)
+		case errors.Is(err, utils.ErrPasswordTooLong):
+			helper.BadRequestResponse(w, "Failed to change password", err)
+		default:
+			helper.InternalServerError(w, "Failed to change password", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Password successfully changed", nil)
+}
+
+func (u *UserHandler) GetLoginLock(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	lock, err := u.userService.GetLoginLock(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Failed to fetch a user")
+		default:
+			helper.InternalServerError(w, "Failed to fetch lock status", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Lock status successfully retrieved", lock)
+}
+
+func (u *UserHandler) UnlockLogin(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if err := u.userService.UnlockLogin(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Failed to fetch a user")
+		default:
+			helper.InternalServerError(w, "Failed to unlock user", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "User successfully unlocked", nil)
+}
+
+// userIdParam resolves the :id path parameter, treating "me" as the
+// authenticated caller.
+func userIdParam(r *http.Request) string {
+	id := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	if id == "me" {
+		id, _ = utils.UserIdFromCtx(r.Context())
+	}
+	return id
+}
+
+// isCaller reports whether the request was made by user id with an access
+// token. API keys never count, as they only act through their scopes.
+func isCaller(r *http.Request, id string) bool {
+	if _, ok := utils.APIKeyIdFromCtx(r.Context()); ok {
+		return false
+	}
+
+	callerId, _ := utils.UserIdFromCtx(r.Context())
+	return callerId != "" && callerId == id
+}
+
 func NewUserHandler(userService service.UserService) *UserHandler {
 	return &UserHandler{
 		userService: userService,
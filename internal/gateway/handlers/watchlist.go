@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/authz"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/service"
+	"net/http"
+	"strconv"
+)
+
+type WatchlistHandler struct {
+	watchlistService service.WatchlistService
+}
+
+func (wh *WatchlistHandler) AddToWatchlist(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if !isCaller(r, id) {
+		helper.ForbiddenResponse(w, "You can only change your own watchlist")
+		return
+	}
+
+	var payload dto.AddWatchlistItemReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "invalid payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateAddWatchlistItemReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	item, err := wh.watchlistService.AddToWatchlist(r.Context(), id, &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Movie not found")
+		case errors.Is(err, repository.ErrDuplicateWatchlistItem):
+			helper.EditConflictResponse(w, "Movie is already on the watchlist", err)
+		default:
+			helper.InternalServerError(w, "Failed to add movie to watchlist", err)
+		}
+		return
+	}
+
+	helper.CreatedResponse(w, "Movie successfully added to watchlist", item)
+}
+
+func (wh *WatchlistHandler) GetWatchlist(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if !authz.Can(r.Context(), authz.ActionRead, authz.User(id)) {
+		helper.ForbiddenResponse(w, "You are not authorized to access this resource")
+		return
+	}
+
+	limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+	if limit < 0 {
+		limit = 10
+	}
+
+	includeTotal, err := helper.ReadBoolQuery(r, "include_total")
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid include_total", err)
+		return
+	}
+
+	items, meta, err := wh.watchlistService.GetWatchlist(r.Context(), id, r.URL.Query().Get("cursor"), limit, includeTotal)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidCursor):
+			helper.BadRequestResponse(w, "Invalid cursor", err)
+		default:
+			helper.InternalServerError(w, "Failed to fetch watchlist", err)
+		}
+		return
+	}
+
+	helper.CursorPaginatedSuccessResponse(w, "Watchlist successfully retrieved", items, *meta)
+}
+
+func (wh *WatchlistHandler) RemoveFromWatchlist(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if !isCaller(r, id) {
+		helper.ForbiddenResponse(w, "You can only change your own watchlist")
+		return
+	}
+
+	imdbId := httprouter.ParamsFromContext(r.Context()).ByName("imdb_id")
+	if err := wh.watchlistService.RemoveFromWatchlist(r.Context(), id, imdbId); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Movie is not on the watchlist")
+		default:
+			helper.InternalServerError(w, "Failed to remove movie from watchlist", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Movie successfully removed from watchlist", nil)
+}
+
+func (wh *WatchlistHandler) AddToHistory(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if !isCaller(r, id) {
+		helper.ForbiddenResponse(w, "You can only change your own history")
+		return
+	}
+
+	var payload dto.AddWatchHistoryReq
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "invalid payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateAddWatchHistoryReq(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "Validation failed")
+		return
+	}
+
+	entry, err := wh.watchlistService.AddToHistory(r.Context(), id, &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Movie not found")
+		default:
+			helper.InternalServerError(w, "Failed to add movie to history", err)
+		}
+		return
+	}
+
+	helper.CreatedResponse(w, "Movie successfully added to history", entry)
+}
+
+func (wh *WatchlistHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if !authz.Can(r.Context(), authz.ActionRead, authz.User(id)) {
+		helper.ForbiddenResponse(w, "You are not authorized to access this resource")
+		return
+	}
+
+	limit, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+	if limit < 0 {
+		limit = 10
+	}
+
+	includeTotal, err := helper.ReadBoolQuery(r, "include_total")
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid include_total", err)
+		return
+	}
+
+	entries, meta, err := wh.watchlistService.GetHistory(r.Context(), id, r.URL.Query().Get("cursor"), limit, includeTotal)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidCursor):
+			helper.BadRequestResponse(w, "Invalid cursor", err)
+		default:
+			helper.InternalServerError(w, "Failed to fetch history", err)
+		}
+		return
+	}
+
+	helper.CursorPaginatedSuccessResponse(w, "History successfully retrieved", entries, *meta)
+}
+
+func (wh *WatchlistHandler) DeleteHistoryEntry(w http.ResponseWriter, r *http.Request) {
+	id := userIdParam(r)
+	if id == "" {
+		helper.BadRequestResponse(w, "Invalid id", errors.New("id is required"))
+		return
+	}
+
+	if !isCaller(r, id) {
+		helper.ForbiddenResponse(w, "You can only change your own history")
+		return
+	}
+
+	entryId := httprouter.ParamsFromContext(r.Context()).ByName("entry_id")
+	if err := wh.watchlistService.DeleteHistoryEntry(r.Context(), id, entryId); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "History entry not found")
+		default:
+			helper.InternalServerError(w, "Failed to delete history entry", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "History entry successfully deleted", nil)
+}
+
+func NewWatchlistHandler(watchlistService service.WatchlistService) *WatchlistHandler {
+	return &WatchlistHandler{
+		watchlistService: watchlistService,
+	}
+}
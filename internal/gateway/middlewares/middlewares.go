@@ -1,15 +1,19 @@
 package middlewares
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/authz"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/service"
 	"github.com/saleh-ghazimoradi/Projectopher/utils"
-	"github.com/tomasen/realip"
 	"golang.org/x/time/rate"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -21,8 +25,20 @@ type client struct {
 }
 
 type Middleware struct {
-	config *config.Config
-	logger *slog.Logger
+	config          *config.Config
+	logger          *slog.Logger
+	denylist        *authz.Denylist
+	permissionCache *authz.PermissionCache
+	keys            *utils.KeySet
+	apiKeys         service.APIKeyService
+	clientIPs       *utils.ClientIPResolver
+}
+
+func (m *Middleware) ClientIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := utils.WithClientIP(r.Context(), m.clientIPs.ClientIP(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
 }
 
 func (m *Middleware) Logging(next http.Handler) http.Handler {
@@ -47,9 +63,10 @@ func (m *Middleware) Recover(next http.Handler) http.Handler {
 func (m *Middleware) CORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, PATCH, DELETE")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
+		w.Header().Set("Access-Control-Expose-Headers", "ETag")
 
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
@@ -86,7 +103,7 @@ func (m *Middleware) RateLimit(next http.Handler) http.Handler {
 	}()
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ip := realip.FromRequest(r)
+		ip, _ := utils.ClientIPFromCtx(r.Context())
 		mu.Lock()
 		if _, found := clients[ip]; !found {
 			clients[ip] = &client{
@@ -108,6 +125,22 @@ func (m *Middleware) RateLimit(next http.Handler) http.Handler {
 
 func (m *Middleware) Authenticate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if apiKey := r.Header.Get("X-API-Key"); apiKey != "" {
+			claims, err := m.apiKeys.Authenticate(r.Context(), apiKey)
+			if err != nil {
+				if errors.Is(err, service.ErrInvalidAPIKey) {
+					helper.UnauthorizedResponse(w, "Invalid api key")
+					return
+				}
+				helper.InternalServerError(w, "Failed to authenticate", err)
+				return
+			}
+
+			ctx := utils.WithAPIKeyId(withClaims(r.Context(), claims), claims.ID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
 			helper.UnauthorizedResponse(w, "Authorization header is required")
@@ -120,26 +153,66 @@ func (m *Middleware) Authenticate(next http.Handler) http.Handler {
 			return
 		}
 
-		claims, err := utils.ValidateToken(tokenParts[1], m.config.JWT.Secret)
+		claims, err := utils.ValidateAccessToken(m.config, m.keys, tokenParts[1])
 		if err != nil {
 			helper.UnauthorizedResponse(w, "Invalid token")
 			return
 		}
 
-		ctx := r.Context()
-		ctx = utils.WithFirstName(ctx, claims.FirstName)
-		ctx = utils.WithLastName(ctx, claims.LastName)
-		ctx = utils.WithEmail(ctx, claims.Email)
-		ctx = utils.WithRole(ctx, claims.Role)
-		ctx = utils.WithUserId(ctx, claims.UserId)
+		if claims.ID != "" && m.denylist.IsRevoked(claims.ID) {
+			helper.UnauthorizedResponse(w, "Token has been revoked")
+			return
+		}
+
+		// The permissions claim is a snapshot from when the token was issued.
+		// Resolving the role again means a permission taken away from a role
+		// stops working straight away rather than when the token expires.
+		rolePermissions, err := m.permissionCache.Permissions(r.Context(), domain.UserRole(claims.Role))
+		if err != nil {
+			helper.InternalServerError(w, "Failed to authenticate", err)
+			return
+		}
+		claims.Permissions = make([]string, len(rolePermissions))
+		for i, p := range rolePermissions {
+			claims.Permissions[i] = string(p)
+		}
 
-		r = r.WithContext(ctx)
+		next.ServeHTTP(w, r.WithContext(withClaims(r.Context(), claims)))
+	})
+}
+
+func withClaims(ctx context.Context, claims *utils.Claims) context.Context {
+	ctx = utils.WithFirstName(ctx, claims.FirstName)
+	ctx = utils.WithLastName(ctx, claims.LastName)
+	ctx = utils.WithEmail(ctx, claims.Email)
+	ctx = utils.WithRole(ctx, claims.Role)
+	ctx = utils.WithUserId(ctx, claims.UserId)
+	ctx = utils.WithPermissions(ctx, claims.Permissions)
+	return ctx
+}
+
+// RequireSession rejects callers authenticated with an API key. It guards
+// credential, MFA, session and account management routes, which a leaked key
+// must never reach.
+func (m *Middleware) RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := utils.APIKeyIdFromCtx(r.Context()); ok {
+			helper.ForbiddenResponse(w, "API keys cannot access this resource")
+			return
+		}
 		next.ServeHTTP(w, r)
 	})
 }
 
+// Admin checks the role rather than a permission, so API keys, which only act
+// through their scopes, are never admitted.
 func (m *Middleware) Admin(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := utils.APIKeyIdFromCtx(r.Context()); ok {
+			helper.ForbiddenResponse(w, "You are not authorized to access this resource")
+			return
+		}
+
 		role, exists := utils.RoleFromCtx(r.Context())
 		if !exists {
 			helper.ForbiddenResponse(w, "You are not authorized to access this resource")
@@ -154,9 +227,27 @@ func (m *Middleware) Admin(next http.Handler) http.Handler {
 	})
 }
 
-func NewMiddleware(config *config.Config, logger *slog.Logger) *Middleware {
+func (m *Middleware) RequirePermission(permission domain.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			permissions, _ := utils.PermissionsFromCtx(r.Context())
+			if !slices.Contains(permissions, string(permission)) {
+				helper.ForbiddenResponse(w, "You are not authorized to access this resource")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func NewMiddleware(config *config.Config, logger *slog.Logger, denylist *authz.Denylist, permissionCache *authz.PermissionCache, keys *utils.KeySet, apiKeys service.APIKeyService, clientIPs *utils.ClientIPResolver) *Middleware {
 	return &Middleware{
-		config: config,
-		logger: logger,
+		config:          config,
+		logger:          logger,
+		denylist:        denylist,
+		permissionCache: permissionCache,
+		keys:            keys,
+		apiKeys:         apiKeys,
+		clientIPs:       clientIPs,
 	}
 }
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/handlers"
+	"net/http"
+)
+
+type APIKeyRoute struct {
+	apiKeyHandler *handlers.APIKeyHandler
+}
+
+func (a *APIKeyRoute) APIKeyRoutes(group *Group) {
+	group.Interactive(http.MethodPost, "/v1/users/:id/api-keys", a.apiKeyHandler.CreateAPIKey)
+	group.Interactive(http.MethodGet, "/v1/users/:id/api-keys", a.apiKeyHandler.GetAPIKeys)
+	group.Interactive(http.MethodDelete, "/v1/users/:id/api-keys/:key_id", a.apiKeyHandler.DeleteAPIKey)
+}
+
+func NewAPIKeyRoute(apiKeyHandler *handlers.APIKeyHandler) *APIKeyRoute {
+	return &APIKeyRoute{
+		apiKeyHandler: apiKeyHandler,
+	}
+}
@@ -1,7 +1,6 @@
 package routes
 
 import (
-	"github.com/julienschmidt/httprouter"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/handlers"
 	"net/http"
 )
@@ -10,11 +9,18 @@ type AuthRoute struct {
 	authHandler *handlers.AuthHandler
 }
 
-func (a *AuthRoute) AuthRoutes(router *httprouter.Router) {
-	router.HandlerFunc(http.MethodPost, "/v1/auth/register", a.authHandler.Register)
-	router.HandlerFunc(http.MethodPost, "/v1/auth/login", a.authHandler.Login)
-	router.HandlerFunc(http.MethodPost, "/v1/auth/refresh_token", a.authHandler.RefreshToken)
-	router.HandlerFunc(http.MethodPost, "/v1/auth/logout", a.authHandler.Logout)
+func (a *AuthRoute) AuthRoutes(group *Group) {
+	group.Public(http.MethodPost, "/v1/auth/register", a.authHandler.Register)
+	group.Public(http.MethodPost, "/v1/auth/login", a.authHandler.Login)
+	group.Public(http.MethodPost, "/v1/auth/mfa/verify", a.authHandler.VerifyMFA)
+	group.Public(http.MethodPost, "/v1/auth/refresh_token", a.authHandler.RefreshToken)
+	group.Public(http.MethodPost, "/v1/auth/logout", a.authHandler.Logout)
+	group.Public(http.MethodPost, "/v1/auth/password/forgot", a.authHandler.ForgotPassword)
+	group.Public(http.MethodPost, "/v1/auth/password/reset", a.authHandler.ResetPassword)
+	group.Public(http.MethodPost, "/v1/auth/verify-email", a.authHandler.VerifyEmail)
+	group.Public(http.MethodPost, "/v1/auth/verify-email/resend", a.authHandler.ResendVerification)
+	group.Public(http.MethodGet, "/v1/auth/oidc/:provider/authorize", a.authHandler.OIDCAuthorize)
+	group.Public(http.MethodPost, "/v1/auth/oidc/:provider/callback", a.authHandler.OIDCCallback)
 }
 
 func NewAuthRoute(authHandler *handlers.AuthHandler) *AuthRoute {
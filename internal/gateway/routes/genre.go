@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/handlers"
+	"net/http"
+)
+
+type GenreRoute struct {
+	genreHandler *handlers.GenreHandler
+}
+
+func (g *GenreRoute) GenreRoutes(group *Group) {
+	group.Permission(http.MethodPost, "/v1/genres", domain.PermissionGenresWrite, g.genreHandler.CreateGenre)
+	group.Public(http.MethodGet, "/v1/genres/:genre_id", g.genreHandler.GetGenre)
+	group.Permission(http.MethodPatch, "/v1/genres/:genre_id", domain.PermissionGenresWrite, g.genreHandler.UpdateGenre)
+	group.Permission(http.MethodDelete, "/v1/genres/:genre_id", domain.PermissionGenresWrite, g.genreHandler.DeleteGenre)
+}
+
+func NewGenreRoute(genreHandler *handlers.GenreHandler) *GenreRoute {
+	return &GenreRoute{
+		genreHandler: genreHandler,
+	}
+}
@@ -0,0 +1,74 @@
+package routes
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/middlewares"
+	"net/http"
+)
+
+type Access int
+
+// Interactive routes are Authenticated routes that only accept access tokens,
+// never API keys.
+const (
+	Public Access = iota
+	Authenticated
+	Interactive
+	Admin
+)
+
+// Group registers routes on the router and applies the middleware chain that
+// matches the access level each route declares.
+type Group struct {
+	router     *httprouter.Router
+	middleware *middlewares.Middleware
+}
+
+func (g *Group) Handle(method, path string, access Access, handler http.HandlerFunc) {
+	g.router.Handler(method, path, g.Wrap(access, handler))
+}
+
+func (g *Group) Public(method, path string, handler http.HandlerFunc) {
+	g.Handle(method, path, Public, handler)
+}
+
+func (g *Group) Authenticated(method, path string, handler http.HandlerFunc) {
+	g.Handle(method, path, Authenticated, handler)
+}
+
+func (g *Group) Interactive(method, path string, handler http.HandlerFunc) {
+	g.Handle(method, path, Interactive, handler)
+}
+
+func (g *Group) Admin(method, path string, handler http.HandlerFunc) {
+	g.Handle(method, path, Admin, handler)
+}
+
+func (g *Group) Permission(method, path string, permission domain.Permission, handler http.HandlerFunc) {
+	g.router.Handler(method, path, g.middleware.Authenticate(g.middleware.RequirePermission(permission)(handler)))
+}
+
+func (g *Group) InteractivePermission(method, path string, permission domain.Permission, handler http.HandlerFunc) {
+	g.router.Handler(method, path, g.middleware.Authenticate(g.middleware.RequireSession(g.middleware.RequirePermission(permission)(handler))))
+}
+
+func (g *Group) Wrap(access Access, handler http.HandlerFunc) http.Handler {
+	switch access {
+	case Authenticated:
+		return g.middleware.Authenticate(handler)
+	case Interactive:
+		return g.middleware.Authenticate(g.middleware.RequireSession(handler))
+	case Admin:
+		return g.middleware.Authenticate(g.middleware.Admin(handler))
+	default:
+		return handler
+	}
+}
+
+func NewGroup(router *httprouter.Router, middleware *middlewares.Middleware) *Group {
+	return &Group{
+		router:     router,
+		middleware: middleware,
+	}
+}
@@ -1,7 +1,6 @@
 package routes
 
 import (
-	"github.com/julienschmidt/httprouter"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/handlers"
 	"net/http"
 )
@@ -10,8 +9,8 @@ type HealthRoute struct {
 	healthHandler *handlers.HealthHandler
 }
 
-func (h *HealthRoute) HealthRoutes(router *httprouter.Router) {
-	router.HandlerFunc(http.MethodGet, "/v1/healthcheck", h.healthHandler.HealthCheck)
+func (h *HealthRoute) HealthRoutes(group *Group) {
+	group.Public(http.MethodGet, "/v1/healthcheck", h.healthHandler.HealthCheck)
 }
 
 func NewHealthRoute(healthHandler *handlers.HealthHandler) *HealthRoute {
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/handlers"
+	"net/http"
+)
+
+type JWKSRoute struct {
+	jwksHandler *handlers.JWKSHandler
+}
+
+func (j *JWKSRoute) JWKSRoutes(group *Group) {
+	group.Public(http.MethodGet, "/.well-known/jwks.json", j.jwksHandler.JWKS)
+}
+
+func NewJWKSRoute(jwksHandler *handlers.JWKSHandler) *JWKSRoute {
+	return &JWKSRoute{
+		jwksHandler: jwksHandler,
+	}
+}
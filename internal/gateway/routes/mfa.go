@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/handlers"
+	"net/http"
+)
+
+type MFARoute struct {
+	mfaHandler *handlers.MFAHandler
+}
+
+func (m *MFARoute) MFARoutes(group *Group) {
+	group.Interactive(http.MethodPost, "/v1/users/:id/mfa/enroll", m.mfaHandler.Enroll)
+	group.Interactive(http.MethodPost, "/v1/users/:id/mfa/confirm", m.mfaHandler.Confirm)
+	group.Interactive(http.MethodDelete, "/v1/users/:id/mfa", m.mfaHandler.Disable)
+}
+
+func NewMFARoute(mfaHandler *handlers.MFAHandler) *MFARoute {
+	return &MFARoute{
+		mfaHandler: mfaHandler,
+	}
+}
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"github.com/julienschmidt/httprouter"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/handlers"
 	"net/http"
 )
@@ -10,11 +11,26 @@ type MovieRoute struct {
 	movieHandler *handlers.MovieHandler
 }
 
-func (m *MovieRoute) MovieRoutes(router *httprouter.Router) {
-	router.HandlerFunc(http.MethodPost, "/v1/movies", m.movieHandler.AddMovie)
-	router.HandlerFunc(http.MethodGet, "/v1/movies/:imdb_id", m.movieHandler.GetMovie)
-	router.HandlerFunc(http.MethodGet, "/v1/movies", m.movieHandler.GetMovies)
+func (m *MovieRoute) MovieRoutes(group *Group) {
+	recommended := group.Wrap(Authenticated, m.movieHandler.GetRecommendedMoviesHandler)
 
+	group.Public(http.MethodGet, "/v1/movies", m.movieHandler.GetMovies)
+	group.Public(http.MethodGet, "/v1/movies/:imdb_id", func(w http.ResponseWriter, r *http.Request) {
+		// httprouter cannot register /v1/movies/recommended next to the
+		// :imdb_id wildcard, so the static path is dispatched from here.
+		if httprouter.ParamsFromContext(r.Context()).ByName("imdb_id") == "recommended" {
+			recommended.ServeHTTP(w, r)
+			return
+		}
+		m.movieHandler.GetMovie(w, r)
+	})
+	group.Public(http.MethodGet, "/v1/genres", m.movieHandler.GetGenres)
+
+	group.Permission(http.MethodPost, "/v1/movies", domain.PermissionMoviesWrite, m.movieHandler.AddMovie)
+	group.Permission(http.MethodPatch, "/v1/movies/:imdb_id", domain.PermissionMoviesWrite, m.movieHandler.UpdateMovie)
+	group.Permission(http.MethodPut, "/v1/movies/:imdb_id", domain.PermissionMoviesWrite, m.movieHandler.ReplaceMovie)
+	group.Permission(http.MethodDelete, "/v1/movies/:imdb_id", domain.PermissionMoviesDelete, m.movieHandler.DeleteMovie)
+	group.Permission(http.MethodPatch, "/v1/movies/:imdb_id/review", domain.PermissionReviewsWrite, m.movieHandler.AdminReviewUpdate)
 }
 
 func NewMovieRoute(movieHandler *handlers.MovieHandler) *MovieRoute {
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/handlers"
+	"net/http"
+)
+
+type MovieListRoute struct {
+	movieListHandler *handlers.MovieListHandler
+}
+
+func (m *MovieListRoute) MovieListRoutes(group *Group) {
+	group.Public(http.MethodGet, "/v1/lists", m.movieListHandler.GetPublicMovieLists)
+	group.Public(http.MethodGet, "/v1/lists/:slug", m.movieListHandler.GetSharedMovieList)
+	group.Authenticated(http.MethodGet, "/v1/users/:id/lists", m.movieListHandler.GetUserMovieLists)
+	group.Authenticated(http.MethodPost, "/v1/users/:id/lists", m.movieListHandler.CreateMovieList)
+	group.Authenticated(http.MethodGet, "/v1/users/:id/lists/:list_id", m.movieListHandler.GetMovieList)
+	group.Authenticated(http.MethodPatch, "/v1/users/:id/lists/:list_id", m.movieListHandler.UpdateMovieList)
+	group.Authenticated(http.MethodDelete, "/v1/users/:id/lists/:list_id", m.movieListHandler.DeleteMovieList)
+	group.Authenticated(http.MethodPost, "/v1/users/:id/lists/:list_id/items", m.movieListHandler.AddMovieListItem)
+	group.Authenticated(http.MethodDelete, "/v1/users/:id/lists/:list_id/items/:imdb_id", m.movieListHandler.RemoveMovieListItem)
+	group.Authenticated(http.MethodPut, "/v1/users/:id/lists/:list_id/order", m.movieListHandler.ReorderMovieList)
+}
+
+func NewMovieListRoute(movieListHandler *handlers.MovieListHandler) *MovieListRoute {
+	return &MovieListRoute{
+		movieListHandler: movieListHandler,
+	}
+}
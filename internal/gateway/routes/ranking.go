@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/handlers"
+	"net/http"
+)
+
+type RankingRoute struct {
+	rankingHandler *handlers.RankingHandler
+}
+
+func (r *RankingRoute) RankingRoutes(group *Group) {
+	group.Permission(http.MethodPost, "/v1/rankings", domain.PermissionRankingsWrite, r.rankingHandler.CreateRanking)
+	group.Public(http.MethodGet, "/v1/rankings", r.rankingHandler.GetRankings)
+	group.Public(http.MethodGet, "/v1/rankings/:ranking_value", r.rankingHandler.GetRanking)
+	group.Permission(http.MethodPatch, "/v1/rankings/:ranking_value", domain.PermissionRankingsWrite, r.rankingHandler.UpdateRanking)
+	group.Permission(http.MethodDelete, "/v1/rankings/:ranking_value", domain.PermissionRankingsWrite, r.rankingHandler.DeleteRanking)
+}
+
+func NewRankingRoute(rankingHandler *handlers.RankingHandler) *RankingRoute {
+	return &RankingRoute{
+		rankingHandler: rankingHandler,
+	}
+}
@@ -8,11 +8,21 @@ import (
 )
 
 type Register struct {
-	healthRoute *HealthRoute
-	authRoute   *AuthRoute
-	movieRoute  *MovieRoute
-	userRoute   *UserRoute
-	middlewares *middlewares.Middleware
+	healthRoute    *HealthRoute
+	authRoute      *AuthRoute
+	movieRoute     *MovieRoute
+	userRoute      *UserRoute
+	genreRoute     *GenreRoute
+	rankingRoute   *RankingRoute
+	roleRoute      *RoleRoute
+	mfaRoute       *MFARoute
+	sessionRoute   *SessionRoute
+	jwksRoute      *JWKSRoute
+	apiKeyRoute    *APIKeyRoute
+	reviewRoute    *ReviewRoute
+	watchlistRoute *WatchlistRoute
+	movieListRoute *MovieListRoute
+	middlewares    *middlewares.Middleware
 }
 
 type Options func(*Register)
@@ -41,6 +51,66 @@ func WithUserRoute(userRoute *UserRoute) Options {
 	}
 }
 
+func WithGenreRoute(genreRoute *GenreRoute) Options {
+	return func(r *Register) {
+		r.genreRoute = genreRoute
+	}
+}
+
+func WithRankingRoute(rankingRoute *RankingRoute) Options {
+	return func(r *Register) {
+		r.rankingRoute = rankingRoute
+	}
+}
+
+func WithRoleRoute(roleRoute *RoleRoute) Options {
+	return func(r *Register) {
+		r.roleRoute = roleRoute
+	}
+}
+
+func WithMFARoute(mfaRoute *MFARoute) Options {
+	return func(r *Register) {
+		r.mfaRoute = mfaRoute
+	}
+}
+
+func WithSessionRoute(sessionRoute *SessionRoute) Options {
+	return func(r *Register) {
+		r.sessionRoute = sessionRoute
+	}
+}
+
+func WithJWKSRoute(jwksRoute *JWKSRoute) Options {
+	return func(r *Register) {
+		r.jwksRoute = jwksRoute
+	}
+}
+
+func WithAPIKeyRoute(apiKeyRoute *APIKeyRoute) Options {
+	return func(r *Register) {
+		r.apiKeyRoute = apiKeyRoute
+	}
+}
+
+func WithReviewRoute(reviewRoute *ReviewRoute) Options {
+	return func(r *Register) {
+		r.reviewRoute = reviewRoute
+	}
+}
+
+func WithWatchlistRoute(watchlistRoute *WatchlistRoute) Options {
+	return func(r *Register) {
+		r.watchlistRoute = watchlistRoute
+	}
+}
+
+func WithMovieListRoute(movieListRoute *MovieListRoute) Options {
+	return func(r *Register) {
+		r.movieListRoute = movieListRoute
+	}
+}
+
 func WithMiddleware(middlewares *middlewares.Middleware) Options {
 	return func(r *Register) {
 		r.middlewares = middlewares
@@ -51,11 +121,22 @@ func (r *Register) RegisterRoutes() http.Handler {
 	router := httprouter.New()
 	router.NotFound = http.HandlerFunc(helper.HTTPRouterNotFoundResponse)
 	router.MethodNotAllowed = http.HandlerFunc(helper.HTTPRouterMethodNotAllowedResponse)
-	r.healthRoute.HealthRoutes(router)
-	r.authRoute.AuthRoutes(router)
-	r.movieRoute.MovieRoutes(router)
-	r.userRoute.UserRoutes(router)
-	return r.middlewares.Recover(r.middlewares.Logging(r.middlewares.CORS(r.middlewares.RateLimit(router))))
+	group := NewGroup(router, r.middlewares)
+	r.healthRoute.HealthRoutes(group)
+	r.authRoute.AuthRoutes(group)
+	r.movieRoute.MovieRoutes(group)
+	r.userRoute.UserRoutes(group)
+	r.genreRoute.GenreRoutes(group)
+	r.rankingRoute.RankingRoutes(group)
+	r.roleRoute.RoleRoutes(group)
+	r.mfaRoute.MFARoutes(group)
+	r.sessionRoute.SessionRoutes(group)
+	r.jwksRoute.JWKSRoutes(group)
+	r.apiKeyRoute.APIKeyRoutes(group)
+	r.reviewRoute.ReviewRoutes(group)
+	r.watchlistRoute.WatchlistRoutes(group)
+	r.movieListRoute.MovieListRoutes(group)
+	return r.middlewares.Recover(r.middlewares.ClientIP(r.middlewares.Logging(r.middlewares.CORS(r.middlewares.RateLimit(router)))))
 }
 
 func NewRegister(opts ...Options) *Register {
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/handlers"
+	"net/http"
+)
+
+type ReviewRoute struct {
+	reviewHandler *handlers.ReviewHandler
+}
+
+func (r *ReviewRoute) ReviewRoutes(group *Group) {
+	group.Public(http.MethodGet, "/v1/movies/:imdb_id/reviews", r.reviewHandler.GetReviews)
+	group.Authenticated(http.MethodPost, "/v1/movies/:imdb_id/reviews", r.reviewHandler.CreateReview)
+	group.Authenticated(http.MethodPatch, "/v1/movies/:imdb_id/reviews", r.reviewHandler.UpdateReview)
+	group.Authenticated(http.MethodDelete, "/v1/movies/:imdb_id/reviews", r.reviewHandler.DeleteReview)
+}
+
+func NewReviewRoute(reviewHandler *handlers.ReviewHandler) *ReviewRoute {
+	return &ReviewRoute{
+		reviewHandler: reviewHandler,
+	}
+}
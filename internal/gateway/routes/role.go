@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/handlers"
+	"net/http"
+)
+
+type RoleRoute struct {
+	roleHandler *handlers.RoleHandler
+}
+
+func (r *RoleRoute) RoleRoutes(group *Group) {
+	group.Permission(http.MethodGet, "/v1/roles", domain.PermissionRolesManage, r.roleHandler.GetRoles)
+	group.Permission(http.MethodPut, "/v1/roles/:name", domain.PermissionRolesManage, r.roleHandler.UpdateRolePermissions)
+}
+
+func NewRoleRoute(roleHandler *handlers.RoleHandler) *RoleRoute {
+	return &RoleRoute{
+		roleHandler: roleHandler,
+	}
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/handlers"
+	"net/http"
+)
+
+type SessionRoute struct {
+	sessionHandler *handlers.SessionHandler
+}
+
+func (s *SessionRoute) SessionRoutes(group *Group) {
+	group.Interactive(http.MethodPost, "/v1/auth/logout-all", s.sessionHandler.LogoutAll)
+	group.Interactive(http.MethodGet, "/v1/users/:id/sessions", s.sessionHandler.GetSessions)
+	group.Interactive(http.MethodDelete, "/v1/users/:id/sessions/:session_id", s.sessionHandler.RevokeSession)
+	group.InteractivePermission(http.MethodDelete, "/v1/users/:id/sessions", domain.PermissionUsersManage, s.sessionHandler.RevokeAllSessions)
+}
+
+func NewSessionRoute(sessionHandler *handlers.SessionHandler) *SessionRoute {
+	return &SessionRoute{
+		sessionHandler: sessionHandler,
+	}
+}
@@ -1,7 +1,7 @@
 package routes
 
 import (
-	"github.com/julienschmidt/httprouter"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/handlers"
 	"net/http"
 )
@@ -10,11 +10,15 @@ type UserRoute struct {
 	userHandler *handlers.UserHandler
 }
 
-func (u *UserRoute) UserRoutes(router *httprouter.Router) {
-	router.HandlerFunc(http.MethodGet, "/v1/users/:id", u.userHandler.GetProfile)
-	router.HandlerFunc(http.MethodGet, "/v1/users", u.userHandler.GetProfiles)
-	router.HandlerFunc(http.MethodPatch, "/v1/users/:id", u.userHandler.UpdateProfile)
-	router.HandlerFunc(http.MethodDelete, "/v1/users/:id", u.userHandler.DeleteProfile)
+func (u *UserRoute) UserRoutes(group *Group) {
+	group.Authenticated(http.MethodGet, "/v1/users/:id", u.userHandler.GetProfile)
+	group.Authenticated(http.MethodGet, "/v1/users", u.userHandler.GetProfiles)
+	group.Interactive(http.MethodPatch, "/v1/users/:id", u.userHandler.UpdateProfile)
+	group.Interactive(http.MethodDelete, "/v1/users/:id", u.userHandler.DeleteProfile)
+	group.Interactive(http.MethodPost, "/v1/users/:id/password", u.userHandler.ChangePassword)
+	group.InteractivePermission(http.MethodPut, "/v1/users/:id/role", domain.PermissionRolesManage, u.userHandler.AssignRole)
+	group.InteractivePermission(http.MethodGet, "/v1/users/:id/lock", domain.PermissionUsersManage, u.userHandler.GetLoginLock)
+	group.InteractivePermission(http.MethodDelete, "/v1/users/:id/lock", domain.PermissionUsersManage, u.userHandler.UnlockLogin)
 }
 
 func NewUserRoute(userHandler *handlers.UserHandler) *UserRoute {
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/gateway/handlers"
+	"net/http"
+)
+
+type WatchlistRoute struct {
+	watchlistHandler *handlers.WatchlistHandler
+}
+
+func (w *WatchlistRoute) WatchlistRoutes(group *Group) {
+	group.Authenticated(http.MethodGet, "/v1/users/:id/watchlist", w.watchlistHandler.GetWatchlist)
+	group.Authenticated(http.MethodPost, "/v1/users/:id/watchlist", w.watchlistHandler.AddToWatchlist)
+	group.Authenticated(http.MethodDelete, "/v1/users/:id/watchlist/:imdb_id", w.watchlistHandler.RemoveFromWatchlist)
+	group.Authenticated(http.MethodGet, "/v1/users/:id/history", w.watchlistHandler.GetHistory)
+	group.Authenticated(http.MethodPost, "/v1/users/:id/history", w.watchlistHandler.AddToHistory)
+	group.Authenticated(http.MethodDelete, "/v1/users/:id/history/:entry_id", w.watchlistHandler.DeleteHistoryEntry)
+}
+
+func NewWatchlistRoute(watchlistHandler *handlers.WatchlistHandler) *WatchlistRoute {
+	return &WatchlistRoute{
+		watchlistHandler: watchlistHandler,
+	}
+}
@@ -0,0 +1,29 @@
+package helper
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func SetETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+func ReadIfMatch(r *http.Request) (*int64, error) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return nil, nil
+	}
+
+	tag := strings.TrimPrefix(strings.TrimSpace(header), "W/")
+	tag = strings.Trim(tag, `"`)
+
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version < 0 {
+		return nil, errors.New("If-Match header must contain a valid version")
+	}
+
+	return &version, nil
+}
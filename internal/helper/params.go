@@ -0,0 +1,58 @@
+package helper
+
+import (
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func ReadIntParam(r *http.Request, name string) (int, error) {
+	value, err := strconv.Atoi(httprouter.ParamsFromContext(r.Context()).ByName(name))
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer", name)
+	}
+	return value, nil
+}
+
+func ReadOptionalIntQuery(r *http.Request, name string) (*int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an integer", name)
+	}
+	return &value, nil
+}
+
+func ReadBoolQuery(r *http.Request, name string) (bool, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return false, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean", name)
+	}
+	return value, nil
+}
+
+// ReadOptionalTimeQuery accepts an RFC 3339 timestamp or a plain date.
+func ReadOptionalTimeQuery(r *http.Request, name string) (*time.Time, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if value, err := time.Parse(layout, raw); err == nil {
+			return &value, nil
+		}
+	}
+	return nil, fmt.Errorf("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", name)
+}
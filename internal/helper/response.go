@@ -23,6 +23,20 @@ type PaginatedMeta struct {
 	TotalPage int64 `json:"total_page"`
 }
 
+type CursorPaginatedResponse struct {
+	Response Response
+	Meta     CursorMeta `json:"meta"`
+}
+
+// CursorMeta describes a page read by cursor. Empty cursors mean there is no
+// page in that direction; Total is only set when the client asked for it.
+type CursorMeta struct {
+	Limit      int64  `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
 func SuccessResponse(w http.ResponseWriter, message string, data any) {
 	resp := Response{
 		Success: true,
@@ -107,3 +121,22 @@ func PaginatedSuccessResponse(w http.ResponseWriter, message string, data any, m
 
 	writeJSON(w, http.StatusOK, paginatedResp)
 }
+
+func CursorPaginatedSuccessResponse(w http.ResponseWriter, message string, data any, meta CursorMeta) {
+	paginatedResp := CursorPaginatedResponse{
+		Response: Response{
+			Success: true,
+			Message: message,
+			Data:    data,
+		},
+		Meta: meta,
+	}
+
+	writeJSON(w, http.StatusOK, paginatedResp)
+}
+
+// JSONResponse writes data without the response envelope, for documents whose
+// shape is fixed by a spec such as a JWKS.
+func JSONResponse(w http.ResponseWriter, data any) {
+	writeJSON(w, http.StatusOK, data)
+}
@@ -0,0 +1,494 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository/mongoDTO"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"strings"
+	"time"
+)
+
+func All() []Migration {
+	return []Migration{
+		{
+			Version:     1,
+			Description: "create unique index on user email",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				return createIndex(ctx, database, "user", mongo.IndexModel{
+					Keys:    bson.D{{Key: "email", Value: 1}},
+					Options: options.Index().SetName("email_1").SetUnique(true),
+				})
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				return dropIndex(ctx, database, "user", "email_1")
+			},
+		},
+		{
+			Version:     2,
+			Description: "create unique index on movie imdb_id",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				return createIndex(ctx, database, "movie", mongo.IndexModel{
+					Keys:    bson.D{{Key: "imdb_id", Value: 1}},
+					Options: options.Index().SetName("imdb_id_1").SetUnique(true),
+				})
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				return dropIndex(ctx, database, "movie", "imdb_id_1")
+			},
+		},
+		{
+			Version:     3,
+			Description: "create ttl index on token expires_at",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				return createIndex(ctx, database, "token", mongo.IndexModel{
+					Keys:    bson.D{{Key: "expires_at", Value: 1}},
+					Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+				})
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				return dropIndex(ctx, database, "token", "expires_at_ttl")
+			},
+		},
+		{
+			Version:     4,
+			Description: "create text index on movie title and admin_review",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				return createIndex(ctx, database, "movie", mongo.IndexModel{
+					Keys: bson.D{
+						{Key: "title", Value: "text"},
+						{Key: "admin_review", Value: "text"},
+					},
+					Options: options.Index().SetName("movie_text").SetWeights(bson.D{
+						{Key: "title", Value: 10},
+						{Key: "admin_review", Value: 1},
+					}),
+				})
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				return dropIndex(ctx, database, "movie", "movie_text")
+			},
+		},
+		{
+			Version:     5,
+			Description: "backfill version on movies and users",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				for _, name := range []string{"movie", "user"} {
+					if _, err := database.Collection(name).UpdateMany(ctx,
+						bson.M{"version": bson.M{"$exists": false}},
+						bson.M{"$set": bson.M{"version": int64(1)}},
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     6,
+			Description: "create unique indexes on genre_id and ranking_value",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				if err := createIndex(ctx, database, "genre", mongo.IndexModel{
+					Keys:    bson.D{{Key: "genre_id", Value: 1}},
+					Options: options.Index().SetName("genre_id_1").SetUnique(true),
+				}); err != nil {
+					return err
+				}
+				return createIndex(ctx, database, "rank", mongo.IndexModel{
+					Keys:    bson.D{{Key: "ranking_value", Value: 1}},
+					Options: options.Index().SetName("ranking_value_1").SetUnique(true),
+				})
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				if err := dropIndex(ctx, database, "genre", "genre_id_1"); err != nil {
+					return err
+				}
+				return dropIndex(ctx, database, "rank", "ranking_value_1")
+			},
+		},
+		{
+			Version:     7,
+			Description: "create roles collection with default permissions",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				if err := createIndex(ctx, database, "role", mongo.IndexModel{
+					Keys:    bson.D{{Key: "name", Value: 1}},
+					Options: options.Index().SetName("name_1").SetUnique(true),
+				}); err != nil {
+					return err
+				}
+
+				now := time.Now()
+				for _, role := range domain.DefaultRoles() {
+					role.CreatedAt = now
+					role.UpdatedAt = now
+					if _, err := database.Collection("role").UpdateOne(ctx,
+						bson.M{"name": string(role.Name)},
+						bson.M{"$setOnInsert": mongoDTO.FromRoleCoreToDTO(&role)},
+						options.UpdateOne().SetUpsert(true),
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				return dropIndex(ctx, database, "role", "name_1")
+			},
+		},
+		{
+			Version:     8,
+			Description: "create indexes on password_reset token_hash and expires_at",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				if err := createIndex(ctx, database, "password_reset", mongo.IndexModel{
+					Keys:    bson.D{{Key: "token_hash", Value: 1}},
+					Options: options.Index().SetName("token_hash_1").SetUnique(true),
+				}); err != nil {
+					return err
+				}
+				return createIndex(ctx, database, "password_reset", mongo.IndexModel{
+					Keys:    bson.D{{Key: "expires_at", Value: 1}},
+					Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+				})
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				return dropIndexes(ctx, database, "password_reset", "token_hash_1", "expires_at_ttl")
+			},
+		},
+		{
+			Version:     9,
+			Description: "mark existing users as email verified",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				_, err := database.Collection("user").UpdateMany(ctx,
+					bson.M{"email_verified": bson.M{"$exists": false}},
+					bson.M{"$set": bson.M{"email_verified": true, "verified_at": time.Now()}},
+				)
+				return err
+			},
+		},
+		{
+			Version:     10,
+			Description: "create ttl index on login_attempt expires_at",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				return createIndex(ctx, database, "login_attempt", mongo.IndexModel{
+					Keys:    bson.D{{Key: "expires_at", Value: 1}},
+					Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+				})
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				return dropIndex(ctx, database, "login_attempt", "expires_at_ttl")
+			},
+		},
+		{
+			Version:     11,
+			Description: "hash stored refresh tokens and index token_hash and family_id",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				collection := database.Collection("token")
+				cursor, err := collection.Find(ctx, bson.M{"token": bson.M{"$exists": true}})
+				if err != nil {
+					return err
+				}
+				defer cursor.Close(ctx)
+
+				for cursor.Next(ctx) {
+					var legacy struct {
+						Id    bson.ObjectID `bson:"_id"`
+						Token string        `bson:"token"`
+					}
+					if err := cursor.Decode(&legacy); err != nil {
+						return err
+					}
+
+					// Every legacy token starts its own family.
+					if _, err := collection.UpdateByID(ctx, legacy.Id, bson.M{
+						"$set":   bson.M{"token_hash": utils.HashToken(legacy.Token), "family_id": legacy.Id.Hex()},
+						"$unset": bson.M{"token": ""},
+					}); err != nil {
+						return err
+					}
+				}
+				if err := cursor.Err(); err != nil {
+					return err
+				}
+
+				if err := createIndex(ctx, database, "token", mongo.IndexModel{
+					Keys:    bson.D{{Key: "token_hash", Value: 1}},
+					Options: options.Index().SetName("token_hash_1").SetUnique(true),
+				}); err != nil {
+					return err
+				}
+				return createIndex(ctx, database, "token", mongo.IndexModel{
+					Keys:    bson.D{{Key: "family_id", Value: 1}},
+					Options: options.Index().SetName("family_id_1"),
+				})
+			},
+		},
+		{
+			Version:     12,
+			Description: "create index on token user_id and last_used_at for session listing",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				return createIndex(ctx, database, "token", mongo.IndexModel{
+					Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "last_used_at", Value: -1}},
+					Options: options.Index().SetName("user_id_1_last_used_at_-1"),
+				})
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				return dropIndex(ctx, database, "token", "user_id_1_last_used_at_-1")
+			},
+		},
+		{
+			Version:     13,
+			Description: "create ttl and revoked_at indexes on revoked_token",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				if err := createIndex(ctx, database, "revoked_token", mongo.IndexModel{
+					Keys:    bson.D{{Key: "expires_at", Value: 1}},
+					Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+				}); err != nil {
+					return err
+				}
+				return createIndex(ctx, database, "revoked_token", mongo.IndexModel{
+					Keys:    bson.D{{Key: "revoked_at", Value: 1}},
+					Options: options.Index().SetName("revoked_at_1"),
+				})
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				return dropIndexes(ctx, database, "revoked_token", "expires_at_ttl", "revoked_at_1")
+			},
+		},
+		{
+			Version:     14,
+			Description: "create key_hash, user_id and ttl indexes on api_key",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				if err := createIndex(ctx, database, "api_key", mongo.IndexModel{
+					Keys:    bson.D{{Key: "key_hash", Value: 1}},
+					Options: options.Index().SetName("key_hash_1").SetUnique(true),
+				}); err != nil {
+					return err
+				}
+				if err := createIndex(ctx, database, "api_key", mongo.IndexModel{
+					Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+					Options: options.Index().SetName("user_id_1_created_at_-1"),
+				}); err != nil {
+					return err
+				}
+				return createIndex(ctx, database, "api_key", mongo.IndexModel{
+					Keys:    bson.D{{Key: "expires_at", Value: 1}},
+					Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+				})
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				return dropIndexes(ctx, database, "api_key", "key_hash_1", "user_id_1_created_at_-1", "expires_at_ttl")
+			},
+		},
+		{
+			Version:     15,
+			Description: "create unique index on user identities and ttl index on oidc_state",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				if err := createIndex(ctx, database, "user", mongo.IndexModel{
+					Keys: bson.D{{Key: "identities.provider", Value: 1}, {Key: "identities.subject", Value: 1}},
+					Options: options.Index().SetName("identities_provider_1_identities_subject_1").SetUnique(true).
+						SetPartialFilterExpression(bson.M{"identities": bson.M{"$exists": true}}),
+				}); err != nil {
+					return err
+				}
+				return createIndex(ctx, database, "oidc_state", mongo.IndexModel{
+					Keys:    bson.D{{Key: "expires_at", Value: 1}},
+					Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+				})
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				if err := dropIndex(ctx, database, "user", "identities_provider_1_identities_subject_1"); err != nil {
+					return err
+				}
+				return dropIndex(ctx, database, "oidc_state", "expires_at_ttl")
+			},
+		},
+		{
+			Version:     16,
+			Description: "create indexes on movie genre, ranking, created_at and title for filtering and sorting",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				_, err := database.Collection("movie").Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "genre.genre_id", Value: 1}}, Options: options.Index().SetName("genre.genre_id_1")},
+					{Keys: bson.D{{Key: "ranking.ranking_value", Value: 1}, {Key: "_id", Value: 1}}, Options: options.Index().SetName("ranking.ranking_value_1__id_1")},
+					{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}, Options: options.Index().SetName("created_at_-1__id_1")},
+					{Keys: bson.D{{Key: "title", Value: 1}, {Key: "_id", Value: 1}}, Options: options.Index().SetName("title_1__id_1")},
+				})
+				return err
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				for _, name := range []string{"genre.genre_id_1", "ranking.ranking_value_1__id_1", "created_at_-1__id_1", "title_1__id_1"} {
+					if err := dropIndex(ctx, database, "movie", name); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     17,
+			Description: "create review indexes and backfill movie rating counters",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				if _, err := database.Collection("review").Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "imdb_id", Value: 1}, {Key: "user_id", Value: 1}}, Options: options.Index().SetName("imdb_id_1_user_id_1").SetUnique(true)},
+					{Keys: bson.D{{Key: "imdb_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}, Options: options.Index().SetName("imdb_id_1_created_at_-1__id_1")},
+					{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("user_id_1")},
+				}); err != nil {
+					return err
+				}
+
+				_, err := database.Collection("movie").UpdateMany(ctx,
+					bson.M{"rating_count": bson.M{"$exists": false}},
+					bson.M{"$set": bson.M{"rating_sum": int64(0), "rating_count": int64(0), "average_rating": float64(0)}},
+				)
+				return err
+			},
+			// The rating counters stay on movies. Up only backfills missing ones
+			// and every review write recounts them, so they remain accurate.
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				return dropIndexes(ctx, database, "review", "imdb_id_1_user_id_1", "imdb_id_1_created_at_-1__id_1", "user_id_1")
+			},
+		},
+		{
+			Version:     18,
+			Description: "create watchlist and watch_history indexes",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				if _, err := database.Collection("watchlist").Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "imdb_id", Value: 1}}, Options: options.Index().SetName("user_id_1_imdb_id_1").SetUnique(true)},
+					{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "added_at", Value: -1}, {Key: "_id", Value: 1}}, Options: options.Index().SetName("user_id_1_added_at_-1__id_1")},
+				}); err != nil {
+					return err
+				}
+
+				return createIndex(ctx, database, "watch_history", mongo.IndexModel{
+					Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "watched_at", Value: -1}, {Key: "_id", Value: 1}},
+					Options: options.Index().SetName("user_id_1_watched_at_-1__id_1"),
+				})
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				if err := dropIndexes(ctx, database, "watchlist", "user_id_1_imdb_id_1", "user_id_1_added_at_-1__id_1"); err != nil {
+					return err
+				}
+				return dropIndex(ctx, database, "watch_history", "user_id_1_watched_at_-1__id_1")
+			},
+		},
+		{
+			Version:     19,
+			Description: "create movie_list indexes",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				_, err := database.Collection("movie_list").Indexes().CreateMany(ctx, []mongo.IndexModel{
+					{Keys: bson.D{{Key: "slug", Value: 1}}, Options: options.Index().SetName("slug_1").SetUnique(true)},
+					{Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("owner_id_1_created_at_-1")},
+					{Keys: bson.D{{Key: "visibility", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}, Options: options.Index().SetName("visibility_1_created_at_-1__id_1")},
+				})
+				return err
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				return dropIndexes(ctx, database, "movie_list", "slug_1", "owner_id_1_created_at_-1", "visibility_1_created_at_-1__id_1")
+			},
+		},
+		{
+			Version:     20,
+			Description: "lowercase user emails",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				return lowercaseUserEmails(ctx, database)
+			},
+		},
+		{
+			Version:     21,
+			Description: "recount movie ratings from reviews",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				cursor, err := database.Collection("movie").Aggregate(ctx, repository.MovieRatingPipeline(bson.M{}, "review", "movie"))
+				if err != nil {
+					return err
+				}
+				return cursor.Close(ctx)
+			},
+		},
+		{
+			Version:     22,
+			Description: "index watchlist and watch_history by imdb_id",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				model := mongo.IndexModel{Keys: bson.D{{Key: "imdb_id", Value: 1}}, Options: options.Index().SetName("imdb_id_1")}
+				if err := createIndex(ctx, database, "watchlist", model); err != nil {
+					return err
+				}
+				return createIndex(ctx, database, "watch_history", model)
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				if err := dropIndex(ctx, database, "watchlist", "imdb_id_1"); err != nil {
+					return err
+				}
+				return dropIndex(ctx, database, "watch_history", "imdb_id_1")
+			},
+		},
+		{
+			Version:     23,
+			Description: "index movie_list items by imdb_id",
+			Up: func(ctx context.Context, database *mongo.Database) error {
+				return createIndex(ctx, database, "movie_list", mongo.IndexModel{
+					Keys:    bson.D{{Key: "items.imdb_id", Value: 1}},
+					Options: options.Index().SetName("items.imdb_id_1"),
+				})
+			},
+			Down: func(ctx context.Context, database *mongo.Database) error {
+				return dropIndex(ctx, database, "movie_list", "items.imdb_id_1")
+			},
+		},
+	}
+}
+
+// lowercaseUserEmails refuses to run while two accounts differ only in the case
+// of their email, since merging them needs a human decision.
+func lowercaseUserEmails(ctx context.Context, database *mongo.Database) error {
+	collection := database.Collection("user")
+	cursor, err := collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"$toLower": "$email"}, "count": bson.M{"$sum": 1}}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	var conflicts []struct {
+		Email string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &conflicts); err != nil {
+		return err
+	}
+
+	if len(conflicts) > 0 {
+		emails := make([]string, len(conflicts))
+		for i, c := range conflicts {
+			emails[i] = c.Email
+		}
+		return fmt.Errorf("users share an email that differs only in case, resolve them first: %s", strings.Join(emails, ", "))
+	}
+
+	_, err = collection.UpdateMany(ctx,
+		bson.M{"$expr": bson.M{"$ne": bson.A{"$email", bson.M{"$toLower": "$email"}}}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"email": bson.M{"$toLower": "$email"}}}}},
+	)
+	return err
+}
+
+func createIndex(ctx context.Context, database *mongo.Database, collectionName string, model mongo.IndexModel) error {
+	_, err := database.Collection(collectionName).Indexes().CreateOne(ctx, model)
+	return err
+}
+
+func dropIndex(ctx context.Context, database *mongo.Database, collectionName, name string) error {
+	return database.Collection(collectionName).Indexes().DropOne(ctx, name)
+}
+
+func dropIndexes(ctx context.Context, database *mongo.Database, collectionName string, names ...string) error {
+	for _, name := range names {
+		if err := dropIndex(ctx, database, collectionName, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
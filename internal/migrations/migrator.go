@@ -0,0 +1,157 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+var ErrIrreversible = errors.New("migration is irreversible")
+
+type Migration struct {
+	Version     int64
+	Description string
+	Up          func(ctx context.Context, database *mongo.Database) error
+	Down        func(ctx context.Context, database *mongo.Database) error
+}
+
+type Status struct {
+	Version     int64
+	Description string
+	Applied     bool
+	AppliedAt   time.Time
+}
+
+type record struct {
+	Version     int64     `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+type Migrator struct {
+	database   *mongo.Database
+	collection *mongo.Collection
+	migrations []Migration
+	logger     *slog.Logger
+}
+
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		m.logger.Info("applying migration", "version", migration.Version, "description", migration.Description)
+		if err := migration.Up(ctx, m.database); err != nil {
+			return count, fmt.Errorf("migration %d failed: %w", migration.Version, err)
+		}
+
+		if _, err := m.collection.InsertOne(ctx, record{
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   time.Now(),
+		}); err != nil {
+			return count, fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		if migration.Down == nil {
+			return count, fmt.Errorf("migration %d: %w", migration.Version, ErrIrreversible)
+		}
+
+		m.logger.Info("reverting migration", "version", migration.Version, "description", migration.Description)
+		if err := migration.Down(ctx, m.database); err != nil {
+			return count, fmt.Errorf("migration %d failed: %w", migration.Version, err)
+		}
+
+		if _, err := m.collection.DeleteOne(ctx, bson.M{"_id": migration.Version}); err != nil {
+			return count, fmt.Errorf("failed to remove migration record %d: %w", migration.Version, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		rec, ok := applied[migration.Version]
+		statuses[i] = Status{
+			Version:     migration.Version,
+			Description: migration.Description,
+			Applied:     ok,
+			AppliedAt:   rec.AppliedAt,
+		}
+	}
+
+	return statuses, nil
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int64]record, error) {
+	cursor, err := m.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var records []record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]record, len(records))
+	for _, rec := range records {
+		applied[rec.Version] = rec
+	}
+
+	return applied, nil
+}
+
+func NewMigrator(database *mongo.Database, collectionName string, logger *slog.Logger, migrations []Migration) *Migrator {
+	sorted := slices.Clone(migrations)
+	slices.SortFunc(sorted, func(a, b Migration) int {
+		return int(a.Version - b.Version)
+	})
+
+	return &Migrator{
+		database:   database,
+		collection: database.Collection(collectionName),
+		migrations: sorted,
+		logger:     logger,
+	}
+}
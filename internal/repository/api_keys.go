@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository/mongoDTO"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"time"
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *domain.APIKey) error
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	GetAPIKeysByUserId(ctx context.Context, userId string) ([]domain.APIKey, error)
+	TouchAPIKey(ctx context.Context, id string, at time.Time) error
+	DeleteAPIKey(ctx context.Context, userId, id string) error
+	DeleteAPIKeysByUserId(ctx context.Context, userId string) error
+}
+
+type apiKeyRepository struct {
+	collection *mongo.Collection
+}
+
+// apiKeyTouchInterval bounds how often last_used_at is written for a key used
+// on every request.
+const apiKeyTouchInterval = time.Minute
+
+func (a *apiKeyRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) error {
+	dto, err := mongoDTO.FromAPIKeyCoreToDTO(key)
+	if err != nil {
+		return err
+	}
+
+	result, err := a.collection.InsertOne(ctx, dto)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(bson.ObjectID); ok {
+		key.Id = oid.Hex()
+	}
+
+	return nil
+}
+
+func (a *apiKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var dto mongoDTO.APIKeyDTO
+
+	if err := a.collection.FindOne(ctx, bson.M{"key_hash": keyHash}).Decode(&dto); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return mongoDTO.FromAPIKeyDTOToCore(&dto), nil
+}
+
+func (a *apiKeyRepository) GetAPIKeysByUserId(ctx context.Context, userId string) ([]domain.APIKey, error) {
+	oid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := a.collection.Find(ctx, bson.M{"user_id": oid}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var dtos []mongoDTO.APIKeyDTO
+	if err := cursor.All(ctx, &dtos); err != nil {
+		return nil, err
+	}
+
+	keys := make([]domain.APIKey, len(dtos))
+	for i := range dtos {
+		keys[i] = *mongoDTO.FromAPIKeyDTOToCore(&dtos[i])
+	}
+
+	return keys, nil
+}
+
+// TouchAPIKey records a use of the key, skipping the write when it was already
+// recorded within apiKeyTouchInterval.
+func (a *apiKeyRepository) TouchAPIKey(ctx context.Context, id string, at time.Time) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrRecordNotFound
+	}
+
+	filter := bson.M{
+		"_id": oid,
+		"$or": bson.A{
+			bson.M{"last_used_at": nil},
+			bson.M{"last_used_at": bson.M{"$lt": at.Add(-apiKeyTouchInterval)}},
+		},
+	}
+
+	_, err = a.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"last_used_at": at}})
+	return err
+}
+
+func (a *apiKeyRepository) DeleteAPIKey(ctx context.Context, userId, id string) error {
+	userOID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return ErrRecordNotFound
+	}
+
+	keyOID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrRecordNotFound
+	}
+
+	result, err := a.collection.DeleteOne(ctx, bson.M{"_id": keyOID, "user_id": userOID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (a *apiKeyRepository) DeleteAPIKeysByUserId(ctx context.Context, userId string) error {
+	oid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.collection.DeleteMany(ctx, bson.M{"user_id": oid})
+	return err
+}
+
+func NewAPIKeyRepository(database *mongo.Database, collectionName string) APIKeyRepository {
+	return &apiKeyRepository{
+		collection: database.Collection(collectionName),
+	}
+}
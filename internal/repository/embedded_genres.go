@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository/mongoDTO"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"time"
+)
+
+func renameEmbeddedGenre(ctx context.Context, collection *mongo.Collection, field string, genre *domain.Genre) error {
+	_, err := collection.UpdateMany(ctx,
+		bson.M{field + ".genre_id": genre.GenreId},
+		bson.M{
+			"$set": bson.M{
+				field + ".$[g].genre_name": genre.GenreName,
+				"updated_at":               time.Now(),
+			},
+			"$inc": bson.M{"version": 1},
+		},
+		options.UpdateMany().SetArrayFilters([]any{bson.M{"g.genre_id": genre.GenreId}}),
+	)
+	return err
+}
+
+// reassignEmbeddedGenre replaces the genre fromId with the target genre. Documents
+// that already reference the target only lose the old entry so the array never
+// ends up holding the same genre twice.
+func reassignEmbeddedGenre(ctx context.Context, collection *mongo.Collection, field string, fromId int, to *domain.Genre) error {
+	if _, err := collection.UpdateMany(ctx,
+		bson.M{"$and": bson.A{
+			bson.M{field + ".genre_id": fromId},
+			bson.M{field + ".genre_id": to.GenreId},
+		}},
+		bson.M{
+			"$pull": bson.M{field: bson.M{"genre_id": fromId}},
+			"$set":  bson.M{"updated_at": time.Now()},
+			"$inc":  bson.M{"version": 1},
+		},
+	); err != nil {
+		return err
+	}
+
+	_, err := collection.UpdateMany(ctx,
+		bson.M{field + ".genre_id": fromId},
+		bson.M{
+			"$set": bson.M{
+				field + ".$[g]": mongoDTO.FromGenreCoreToDTO(to),
+				"updated_at":    time.Now(),
+			},
+			"$inc": bson.M{"version": 1},
+		},
+		options.UpdateMany().SetArrayFilters([]any{bson.M{"g.genre_id": fromId}}),
+	)
+	return err
+}
+
+func countEmbeddedGenre(ctx context.Context, collection *mongo.Collection, field string, genreId int) (int64, error) {
+	return collection.CountDocuments(ctx, bson.M{field + ".genre_id": genreId})
+}
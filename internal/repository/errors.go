@@ -3,7 +3,12 @@ package repository
 import "errors"
 
 var (
-	ErrRecordNotFound = errors.New("record not found")
-	ErrEditConflict   = errors.New("edit conflict")
-	ErrDuplicateEmail = errors.New("duplicate email")
+	ErrRecordNotFound         = errors.New("record not found")
+	ErrEditConflict           = errors.New("edit conflict")
+	ErrDuplicateEmail         = errors.New("duplicate email")
+	ErrDuplicateImdb          = errors.New("duplicate imdb id")
+	ErrDuplicateGenre         = errors.New("duplicate genre")
+	ErrDuplicateRanking       = errors.New("duplicate ranking")
+	ErrDuplicateReview        = errors.New("duplicate review")
+	ErrDuplicateWatchlistItem = errors.New("duplicate watchlist item")
 )
@@ -2,20 +2,54 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/repository/mongoDTO"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type GenreRepository interface {
+	CreateGenre(ctx context.Context, genre *domain.Genre) error
+	GetGenre(ctx context.Context, genreId int) (*domain.Genre, error)
 	GetGenres(ctx context.Context) ([]domain.Genre, error)
+	UpdateGenre(ctx context.Context, genre *domain.Genre) error
+	DeleteGenre(ctx context.Context, genreId int) error
+	UpsertGenre(ctx context.Context, genre *domain.Genre) error
 }
 
 type genreRepository struct {
 	collection *mongo.Collection
 }
 
+func (g *genreRepository) CreateGenre(ctx context.Context, genre *domain.Genre) error {
+	if _, err := g.collection.InsertOne(ctx, mongoDTO.FromGenreCoreToDTO(genre)); err != nil {
+		switch {
+		case mongo.IsDuplicateKeyError(err):
+			return ErrDuplicateGenre
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *genreRepository) GetGenre(ctx context.Context, genreId int) (*domain.Genre, error) {
+	var dto mongoDTO.GenreDTO
+
+	if err := g.collection.FindOne(ctx, bson.M{"genre_id": genreId}).Decode(&dto); err != nil {
+		switch {
+		case errors.Is(err, mongo.ErrNoDocuments):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return mongoDTO.FromGenreDTOToCore(&dto), nil
+}
+
 func (g *genreRepository) GetGenres(ctx context.Context) ([]domain.Genre, error) {
 	cursor, err := g.collection.Find(ctx, bson.M{})
 	if err != nil {
@@ -36,6 +70,45 @@ func (g *genreRepository) GetGenres(ctx context.Context) ([]domain.Genre, error)
 	return genres, nil
 }
 
+func (g *genreRepository) UpdateGenre(ctx context.Context, genre *domain.Genre) error {
+	result, err := g.collection.UpdateOne(ctx,
+		bson.M{"genre_id": genre.GenreId},
+		bson.M{"$set": bson.M{"genre_name": genre.GenreName}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (g *genreRepository) DeleteGenre(ctx context.Context, genreId int) error {
+	result, err := g.collection.DeleteOne(ctx, bson.M{"genre_id": genreId})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (g *genreRepository) UpsertGenre(ctx context.Context, genre *domain.Genre) error {
+	dto := mongoDTO.FromGenreCoreToDTO(genre)
+	_, err := g.collection.UpdateOne(ctx,
+		bson.M{"genre_id": dto.GenreId},
+		bson.M{"$set": dto},
+		options.UpdateOne().SetUpsert(true),
+	)
+	return err
+}
+
 func NewGenresRepository(database *mongo.Database, collectionName string) GenreRepository {
 	return &genreRepository{
 		collection: database.Collection(collectionName),
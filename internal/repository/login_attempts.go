@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository/mongoDTO"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"time"
+)
+
+type LoginAttemptRepository interface {
+	GetLoginAttempt(ctx context.Context, key string) (*domain.LoginAttempt, error)
+	RecordFailure(ctx context.Context, key string, at, expiresAt time.Time) (*domain.LoginAttempt, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	DeleteLoginAttempt(ctx context.Context, key string) error
+}
+
+type loginAttemptRepository struct {
+	collection *mongo.Collection
+}
+
+func (l *loginAttemptRepository) GetLoginAttempt(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	var dto mongoDTO.LoginAttemptDTO
+
+	err := l.collection.FindOne(ctx, bson.M{
+		"_id":        key,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}).Decode(&dto)
+
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return mongoDTO.FromLoginAttemptDTOToCore(&dto), nil
+}
+
+// RecordFailure increments the failure counter for key, starting over when
+// the previous record already expired but has not been reaped by the TTL
+// monitor yet.
+func (l *loginAttemptRepository) RecordFailure(ctx context.Context, key string, at, expiresAt time.Time) (*domain.LoginAttempt, error) {
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"failures": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{"$expires_at", at}},
+				bson.M{"$add": bson.A{"$failures", 1}},
+				1,
+			}},
+			"last_failure_at": at,
+			"expires_at":      bson.M{"$max": bson.A{expiresAt, "$locked_until"}},
+		}}},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var dto mongoDTO.LoginAttemptDTO
+	if err := l.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&dto); err != nil {
+		return nil, err
+	}
+
+	return mongoDTO.FromLoginAttemptDTOToCore(&dto), nil
+}
+
+func (l *loginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"locked_until": until,
+			"expires_at":   bson.M{"$max": bson.A{until, "$expires_at"}},
+		}}},
+	}
+
+	_, err := l.collection.UpdateOne(ctx, bson.M{"_id": key}, update)
+	return err
+}
+
+func (l *loginAttemptRepository) DeleteLoginAttempt(ctx context.Context, key string) error {
+	_, err := l.collection.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
+
+func NewLoginAttemptRepository(database *mongo.Database, collectionName string) LoginAttemptRepository {
+	return &loginAttemptRepository{
+		collection: database.Collection(collectionName),
+	}
+}
@@ -0,0 +1,70 @@
+package mongoDTO
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+type APIKeyDTO struct {
+	Id         bson.ObjectID `bson:"_id,omitempty"`
+	UserId     bson.ObjectID `bson:"user_id"`
+	Name       string        `bson:"name"`
+	Prefix     string        `bson:"prefix"`
+	KeyHash    string        `bson:"key_hash"`
+	Scopes     []string      `bson:"scopes"`
+	ExpiresAt  *time.Time    `bson:"expires_at"`
+	LastUsedAt *time.Time    `bson:"last_used_at"`
+	CreatedAt  time.Time     `bson:"created_at"`
+}
+
+func FromAPIKeyCoreToDTO(input *domain.APIKey) (*APIKeyDTO, error) {
+	userOID, err := bson.ObjectIDFromHex(input.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	var keyOID bson.ObjectID
+	if input.Id != "" {
+		keyOID, err = bson.ObjectIDFromHex(input.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	scopes := make([]string, len(input.Scopes))
+	for i, s := range input.Scopes {
+		scopes[i] = string(s)
+	}
+
+	return &APIKeyDTO{
+		Id:         keyOID,
+		UserId:     userOID,
+		Name:       input.Name,
+		Prefix:     input.Prefix,
+		KeyHash:    input.KeyHash,
+		Scopes:     scopes,
+		ExpiresAt:  input.ExpiresAt,
+		LastUsedAt: input.LastUsedAt,
+		CreatedAt:  input.CreatedAt,
+	}, nil
+}
+
+func FromAPIKeyDTOToCore(input *APIKeyDTO) *domain.APIKey {
+	scopes := make([]domain.Permission, len(input.Scopes))
+	for i, s := range input.Scopes {
+		scopes[i] = domain.Permission(s)
+	}
+
+	return &domain.APIKey{
+		Id:         input.Id.Hex(),
+		UserId:     input.UserId.Hex(),
+		Name:       input.Name,
+		Prefix:     input.Prefix,
+		KeyHash:    input.KeyHash,
+		Scopes:     scopes,
+		ExpiresAt:  input.ExpiresAt,
+		LastUsedAt: input.LastUsedAt,
+		CreatedAt:  input.CreatedAt,
+	}
+}
@@ -0,0 +1,24 @@
+package mongoDTO
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"time"
+)
+
+type LoginAttemptDTO struct {
+	Key           string     `bson:"_id"`
+	Failures      int        `bson:"failures"`
+	LastFailureAt time.Time  `bson:"last_failure_at"`
+	LockedUntil   *time.Time `bson:"locked_until"`
+	ExpiresAt     time.Time  `bson:"expires_at"`
+}
+
+func FromLoginAttemptDTOToCore(input *LoginAttemptDTO) *domain.LoginAttempt {
+	return &domain.LoginAttempt{
+		Key:           input.Key,
+		Failures:      input.Failures,
+		LastFailureAt: input.LastFailureAt,
+		LockedUntil:   input.LockedUntil,
+		ExpiresAt:     input.ExpiresAt,
+	}
+}
@@ -0,0 +1,90 @@
+package mongoDTO
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+type MovieListItemDTO struct {
+	ImdbId  string    `bson:"imdb_id"`
+	Note    string    `bson:"note"`
+	AddedAt time.Time `bson:"added_at"`
+}
+
+type MovieListDTO struct {
+	Id          bson.ObjectID      `bson:"_id,omitempty"`
+	OwnerId     bson.ObjectID      `bson:"owner_id"`
+	Title       string             `bson:"title"`
+	Description string             `bson:"description"`
+	Visibility  string             `bson:"visibility"`
+	Slug        string             `bson:"slug"`
+	Items       []MovieListItemDTO `bson:"items"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+	Version     int64              `bson:"version"`
+}
+
+func FromMovieListItemsCoreToDTO(input []domain.MovieListItem) []MovieListItemDTO {
+	items := make([]MovieListItemDTO, len(input))
+	for i, item := range input {
+		items[i] = MovieListItemDTO{
+			ImdbId:  item.ImdbId,
+			Note:    item.Note,
+			AddedAt: item.AddedAt,
+		}
+	}
+	return items
+}
+
+func FromMovieListCoreToDTO(input *domain.MovieList) (*MovieListDTO, error) {
+	ownerOID, err := bson.ObjectIDFromHex(input.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+
+	var listOID bson.ObjectID
+	if input.Id != "" {
+		listOID, err = bson.ObjectIDFromHex(input.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &MovieListDTO{
+		Id:          listOID,
+		OwnerId:     ownerOID,
+		Title:       input.Title,
+		Description: input.Description,
+		Visibility:  string(input.Visibility),
+		Slug:        input.Slug,
+		Items:       FromMovieListItemsCoreToDTO(input.Items),
+		CreatedAt:   input.CreatedAt,
+		UpdatedAt:   input.UpdatedAt,
+		Version:     input.Version,
+	}, nil
+}
+
+func FromMovieListDTOToCore(input *MovieListDTO) *domain.MovieList {
+	items := make([]domain.MovieListItem, len(input.Items))
+	for i, item := range input.Items {
+		items[i] = domain.MovieListItem{
+			ImdbId:  item.ImdbId,
+			Note:    item.Note,
+			AddedAt: item.AddedAt,
+		}
+	}
+
+	return &domain.MovieList{
+		Id:          input.Id.Hex(),
+		OwnerId:     input.OwnerId.Hex(),
+		Title:       input.Title,
+		Description: input.Description,
+		Visibility:  domain.ListVisibility(input.Visibility),
+		Slug:        input.Slug,
+		Items:       items,
+		CreatedAt:   input.CreatedAt,
+		UpdatedAt:   input.UpdatedAt,
+		Version:     input.Version,
+	}
+}
@@ -6,17 +6,23 @@ import (
 	"time"
 )
 
+// MovieDTO carries rating_sum, which the domain type lacks, so average_rating
+// can be recomputed in place when a review changes.
 type MovieDTO struct {
-	Id          bson.ObjectID `bson:"_id,omitempty"`
-	ImdbId      string        `bson:"imdb_id"`
-	Title       string        `bson:"title"`
-	PosterPath  string        `bson:"poster_path"`
-	YoutubeId   string        `bson:"youtube_id"`
-	Genre       []GenreDTO    `bson:"genre"`
-	AdminReview string        `bson:"admin_review"`
-	Ranking     RankingDTO    `bson:"ranking"`
-	CreatedAt   time.Time     `bson:"created_at"`
-	UpdatedAt   time.Time     `bson:"updated_at"`
+	Id            bson.ObjectID `bson:"_id,omitempty"`
+	ImdbId        string        `bson:"imdb_id"`
+	Title         string        `bson:"title"`
+	PosterPath    string        `bson:"poster_path"`
+	YoutubeId     string        `bson:"youtube_id"`
+	Genre         []GenreDTO    `bson:"genre"`
+	AdminReview   string        `bson:"admin_review"`
+	Ranking       RankingDTO    `bson:"ranking"`
+	RatingSum     int64         `bson:"rating_sum"`
+	RatingCount   int64         `bson:"rating_count"`
+	AverageRating float64       `bson:"average_rating"`
+	CreatedAt     time.Time     `bson:"created_at"`
+	UpdatedAt     time.Time     `bson:"updated_at"`
+	Version       int64         `bson:"version"`
 }
 
 func FromMovieCoreToDTO(input *domain.Movie) (*MovieDTO, error) {
@@ -31,16 +37,19 @@ func FromMovieCoreToDTO(input *domain.Movie) (*MovieDTO, error) {
 	}
 
 	dto := &MovieDTO{
-		Id:          oid,
-		ImdbId:      input.ImdbId,
-		Title:       input.Title,
-		PosterPath:  input.PosterPath,
-		YoutubeId:   input.YoutubeId,
-		Genre:       make([]GenreDTO, len(input.Genres)),
-		AdminReview: input.AdminReview,
-		Ranking:     *FromRankingCoreToDTO(&input.Ranking),
-		CreatedAt:   input.CreatedAt,
-		UpdatedAt:   input.UpdatedAt,
+		Id:            oid,
+		ImdbId:        input.ImdbId,
+		Title:         input.Title,
+		PosterPath:    input.PosterPath,
+		YoutubeId:     input.YoutubeId,
+		Genre:         make([]GenreDTO, len(input.Genres)),
+		AdminReview:   input.AdminReview,
+		Ranking:       *FromRankingCoreToDTO(&input.Ranking),
+		RatingCount:   input.RatingCount,
+		AverageRating: input.AverageRating,
+		CreatedAt:     input.CreatedAt,
+		UpdatedAt:     input.UpdatedAt,
+		Version:       input.Version,
 	}
 
 	for i := range input.Genres {
@@ -52,16 +61,19 @@ func FromMovieCoreToDTO(input *domain.Movie) (*MovieDTO, error) {
 
 func FromMovieDTOToCore(input *MovieDTO) *domain.Movie {
 	core := &domain.Movie{
-		Id:          input.Id.Hex(),
-		ImdbId:      input.ImdbId,
-		Title:       input.Title,
-		PosterPath:  input.PosterPath,
-		YoutubeId:   input.YoutubeId,
-		Genres:      make([]domain.Genre, len(input.Genre)),
-		AdminReview: input.AdminReview,
-		Ranking:     *FromRankingDTOToCore(&input.Ranking),
-		CreatedAt:   input.CreatedAt,
-		UpdatedAt:   input.UpdatedAt,
+		Id:            input.Id.Hex(),
+		ImdbId:        input.ImdbId,
+		Title:         input.Title,
+		PosterPath:    input.PosterPath,
+		YoutubeId:     input.YoutubeId,
+		Genres:        make([]domain.Genre, len(input.Genre)),
+		AdminReview:   input.AdminReview,
+		Ranking:       *FromRankingDTOToCore(&input.Ranking),
+		AverageRating: input.AverageRating,
+		RatingCount:   input.RatingCount,
+		CreatedAt:     input.CreatedAt,
+		UpdatedAt:     input.UpdatedAt,
+		Version:       input.Version,
 	}
 
 	for i, g := range input.Genre {
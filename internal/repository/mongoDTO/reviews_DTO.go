@@ -0,0 +1,54 @@
+package mongoDTO
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+type ReviewDTO struct {
+	Id        bson.ObjectID `bson:"_id,omitempty"`
+	ImdbId    string        `bson:"imdb_id"`
+	UserId    bson.ObjectID `bson:"user_id"`
+	Rating    int           `bson:"rating"`
+	Body      string        `bson:"body"`
+	CreatedAt time.Time     `bson:"created_at"`
+	UpdatedAt time.Time     `bson:"updated_at"`
+}
+
+func FromReviewCoreToDTO(input *domain.Review) (*ReviewDTO, error) {
+	userOID, err := bson.ObjectIDFromHex(input.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviewOID bson.ObjectID
+	if input.Id != "" {
+		reviewOID, err = bson.ObjectIDFromHex(input.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ReviewDTO{
+		Id:        reviewOID,
+		ImdbId:    input.ImdbId,
+		UserId:    userOID,
+		Rating:    input.Rating,
+		Body:      input.Body,
+		CreatedAt: input.CreatedAt,
+		UpdatedAt: input.UpdatedAt,
+	}, nil
+}
+
+func FromReviewDTOToCore(input *ReviewDTO) *domain.Review {
+	return &domain.Review{
+		Id:        input.Id.Hex(),
+		ImdbId:    input.ImdbId,
+		UserId:    input.UserId.Hex(),
+		Rating:    input.Rating,
+		Body:      input.Body,
+		CreatedAt: input.CreatedAt,
+		UpdatedAt: input.UpdatedAt,
+	}
+}
@@ -0,0 +1,41 @@
+package mongoDTO
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"time"
+)
+
+type RoleDTO struct {
+	Name        string    `bson:"name"`
+	Permissions []string  `bson:"permissions"`
+	CreatedAt   time.Time `bson:"created_at"`
+	UpdatedAt   time.Time `bson:"updated_at"`
+}
+
+func FromRoleCoreToDTO(input *domain.Role) *RoleDTO {
+	permissions := make([]string, len(input.Permissions))
+	for i, p := range input.Permissions {
+		permissions[i] = string(p)
+	}
+
+	return &RoleDTO{
+		Name:        string(input.Name),
+		Permissions: permissions,
+		CreatedAt:   input.CreatedAt,
+		UpdatedAt:   input.UpdatedAt,
+	}
+}
+
+func FromRoleDTOToCore(input *RoleDTO) *domain.Role {
+	permissions := make([]domain.Permission, len(input.Permissions))
+	for i, p := range input.Permissions {
+		permissions[i] = domain.Permission(p)
+	}
+
+	return &domain.Role{
+		Name:        domain.UserRole(input.Name),
+		Permissions: permissions,
+		CreatedAt:   input.CreatedAt,
+		UpdatedAt:   input.UpdatedAt,
+	}
+}
@@ -7,14 +7,80 @@ import (
 )
 
 type RefreshTokenDTO struct {
+	Id               bson.ObjectID `bson:"_id,omitempty"`
+	UserId           bson.ObjectID `bson:"user_id"`
+	TokenHash        string        `bson:"token_hash"`
+	FamilyId         string        `bson:"family_id"`
+	AccessTokenId    string        `bson:"access_token_id"`
+	AccessExpiresAt  time.Time     `bson:"access_expires_at"`
+	UserAgent        string        `bson:"user_agent"`
+	IP               string        `bson:"ip"`
+	SessionCreatedAt time.Time     `bson:"session_created_at"`
+	LastUsedAt       time.Time     `bson:"last_used_at"`
+	ExpiresAt        time.Time     `bson:"expires_at"`
+	CreatedAt        time.Time     `bson:"created_at"`
+	RotatedAt        *time.Time    `bson:"rotated_at"`
+}
+
+func FromRefreshTokenCoreToDTO(input *domain.RefreshToken) (*RefreshTokenDTO, error) {
+	userOID, err := bson.ObjectIDFromHex(input.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokenOID bson.ObjectID
+	if input.Id != "" {
+		tokenOID, err = bson.ObjectIDFromHex(input.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &RefreshTokenDTO{
+		Id:               tokenOID,
+		UserId:           userOID,
+		TokenHash:        input.TokenHash,
+		FamilyId:         input.FamilyId,
+		AccessTokenId:    input.AccessTokenId,
+		AccessExpiresAt:  input.AccessExpiresAt,
+		UserAgent:        input.UserAgent,
+		IP:               input.IP,
+		SessionCreatedAt: input.SessionCreatedAt,
+		LastUsedAt:       input.LastUsedAt,
+		ExpiresAt:        input.ExpiresAt,
+		CreatedAt:        input.CreatedAt,
+		RotatedAt:        input.RotatedAt,
+	}, nil
+}
+
+func FromRefreshTokenDTOToCore(input *RefreshTokenDTO) *domain.RefreshToken {
+	return &domain.RefreshToken{
+		Id:               input.Id.Hex(),
+		UserId:           input.UserId.Hex(),
+		TokenHash:        input.TokenHash,
+		FamilyId:         input.FamilyId,
+		AccessTokenId:    input.AccessTokenId,
+		AccessExpiresAt:  input.AccessExpiresAt,
+		UserAgent:        input.UserAgent,
+		IP:               input.IP,
+		SessionCreatedAt: input.SessionCreatedAt,
+		LastUsedAt:       input.LastUsedAt,
+		ExpiresAt:        input.ExpiresAt,
+		CreatedAt:        input.CreatedAt,
+		RotatedAt:        input.RotatedAt,
+	}
+}
+
+type PasswordResetTokenDTO struct {
 	Id        bson.ObjectID `bson:"_id,omitempty"`
 	UserId    bson.ObjectID `bson:"user_id"`
-	Token     string        `bson:"token"`
+	TokenHash string        `bson:"token_hash"`
 	ExpiresAt time.Time     `bson:"expires_at"`
 	CreatedAt time.Time     `bson:"created_at"`
+	UsedAt    *time.Time    `bson:"used_at"`
 }
 
-func FromRefreshTokenCoreToDTO(input *domain.RefreshToken) (*RefreshTokenDTO, error) {
+func FromPasswordResetTokenCoreToDTO(input *domain.PasswordResetToken) (*PasswordResetTokenDTO, error) {
 	userOID, err := bson.ObjectIDFromHex(input.UserId)
 	if err != nil {
 		return nil, err
@@ -28,21 +94,76 @@ func FromRefreshTokenCoreToDTO(input *domain.RefreshToken) (*RefreshTokenDTO, er
 		}
 	}
 
-	return &RefreshTokenDTO{
+	return &PasswordResetTokenDTO{
 		Id:        tokenOID,
 		UserId:    userOID,
-		Token:     input.Token,
+		TokenHash: input.TokenHash,
 		ExpiresAt: input.ExpiresAt,
 		CreatedAt: input.CreatedAt,
+		UsedAt:    input.UsedAt,
 	}, nil
 }
 
-func FromRefreshTokenDTOToCore(input *RefreshTokenDTO) *domain.RefreshToken {
-	return &domain.RefreshToken{
+func FromPasswordResetTokenDTOToCore(input *PasswordResetTokenDTO) *domain.PasswordResetToken {
+	return &domain.PasswordResetToken{
 		Id:        input.Id.Hex(),
 		UserId:    input.UserId.Hex(),
-		Token:     input.Token,
+		TokenHash: input.TokenHash,
 		ExpiresAt: input.ExpiresAt,
 		CreatedAt: input.CreatedAt,
+		UsedAt:    input.UsedAt,
+	}
+}
+
+type RevokedTokenDTO struct {
+	Jti       string    `bson:"_id"`
+	UserId    string    `bson:"user_id"`
+	ExpiresAt time.Time `bson:"expires_at"`
+	RevokedAt time.Time `bson:"revoked_at"`
+}
+
+func FromRevokedTokenCoreToDTO(input *domain.RevokedToken) *RevokedTokenDTO {
+	return &RevokedTokenDTO{
+		Jti:       input.Jti,
+		UserId:    input.UserId,
+		ExpiresAt: input.ExpiresAt,
+		RevokedAt: input.RevokedAt,
+	}
+}
+
+func FromRevokedTokenDTOToCore(input *RevokedTokenDTO) *domain.RevokedToken {
+	return &domain.RevokedToken{
+		Jti:       input.Jti,
+		UserId:    input.UserId,
+		ExpiresAt: input.ExpiresAt,
+		RevokedAt: input.RevokedAt,
+	}
+}
+
+type OIDCStateDTO struct {
+	State        string    `bson:"_id"`
+	Provider     string    `bson:"provider"`
+	CodeVerifier string    `bson:"code_verifier"`
+	Nonce        string    `bson:"nonce"`
+	ExpiresAt    time.Time `bson:"expires_at"`
+}
+
+func FromOIDCStateCoreToDTO(input *domain.OIDCState) *OIDCStateDTO {
+	return &OIDCStateDTO{
+		State:        input.State,
+		Provider:     input.Provider,
+		CodeVerifier: input.CodeVerifier,
+		Nonce:        input.Nonce,
+		ExpiresAt:    input.ExpiresAt,
+	}
+}
+
+func FromOIDCStateDTOToCore(input *OIDCStateDTO) *domain.OIDCState {
+	return &domain.OIDCState{
+		State:        input.State,
+		Provider:     input.Provider,
+		CodeVerifier: input.CodeVerifier,
+		Nonce:        input.Nonce,
+		ExpiresAt:    input.ExpiresAt,
 	}
 }
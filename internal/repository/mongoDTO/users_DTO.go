@@ -8,15 +8,34 @@ import (
 )
 
 type UserDTO struct {
-	Id             bson.ObjectID `bson:"_id,omitempty"`
-	FirstName      string        `bson:"first_name"`
-	LastName       string        `bson:"last_name"`
-	Email          string        `bson:"email"`
-	Password       string        `bson:"password"`
-	Role           string        `bson:"role"`
-	CreatedAt      time.Time     `bson:"created_at"`
-	UpdatedAt      time.Time     `bson:"updated_at"`
-	FavoriteGenres []GenreDTO    `bson:"favorite_genres"`
+	Id             bson.ObjectID     `bson:"_id,omitempty"`
+	FirstName      string            `bson:"first_name"`
+	LastName       string            `bson:"last_name"`
+	Email          string            `bson:"email"`
+	Password       string            `bson:"password"`
+	Role           string            `bson:"role"`
+	CreatedAt      time.Time         `bson:"created_at"`
+	UpdatedAt      time.Time         `bson:"updated_at"`
+	FavoriteGenres []GenreDTO        `bson:"favorite_genres"`
+	Version        int64             `bson:"version"`
+	EmailVerified  bool              `bson:"email_verified"`
+	VerifiedAt     *time.Time        `bson:"verified_at"`
+	MFA            UserMFADTO        `bson:"mfa"`
+	Identities     []UserIdentityDTO `bson:"identities,omitempty"`
+}
+
+type UserIdentityDTO struct {
+	Provider string    `bson:"provider"`
+	Subject  string    `bson:"subject"`
+	LinkedAt time.Time `bson:"linked_at"`
+}
+
+type UserMFADTO struct {
+	Enabled       bool     `bson:"enabled"`
+	Secret        string   `bson:"secret,omitempty"`
+	PendingSecret string   `bson:"pending_secret,omitempty"`
+	RecoveryCodes []string `bson:"recovery_codes,omitempty"`
+	LastStep      int64    `bson:"last_step"`
 }
 
 func FromUserCoreToDTO(input *domain.User) (*UserDTO, error) {
@@ -38,6 +57,11 @@ func FromUserCoreToDTO(input *domain.User) (*UserDTO, error) {
 		}
 	}
 
+	identities := make([]UserIdentityDTO, len(input.Identities))
+	for i, identity := range input.Identities {
+		identities[i] = UserIdentityDTO(identity)
+	}
+
 	return &UserDTO{
 		Id:             objectID,
 		FirstName:      input.FirstName,
@@ -48,6 +72,11 @@ func FromUserCoreToDTO(input *domain.User) (*UserDTO, error) {
 		CreatedAt:      input.CreatedAt,
 		UpdatedAt:      input.UpdatedAt,
 		FavoriteGenres: genres,
+		Version:        input.Version,
+		EmailVerified:  input.EmailVerified,
+		VerifiedAt:     input.VerifiedAt,
+		MFA:            UserMFADTO(input.MFA),
+		Identities:     identities,
 	}, nil
 }
 
@@ -60,6 +89,11 @@ func FromUserDTOToCore(input *UserDTO) *domain.User {
 		}
 	}
 
+	identities := make([]domain.UserIdentity, len(input.Identities))
+	for i, identity := range input.Identities {
+		identities[i] = domain.UserIdentity(identity)
+	}
+
 	return &domain.User{
 		Id:             input.Id.Hex(),
 		FirstName:      input.FirstName,
@@ -70,5 +104,10 @@ func FromUserDTOToCore(input *UserDTO) *domain.User {
 		CreatedAt:      input.CreatedAt,
 		UpdatedAt:      input.UpdatedAt,
 		FavoriteGenres: genres,
+		Version:        input.Version,
+		EmailVerified:  input.EmailVerified,
+		VerifiedAt:     input.VerifiedAt,
+		MFA:            domain.UserMFA(input.MFA),
+		Identities:     identities,
 	}
 }
@@ -0,0 +1,86 @@
+package mongoDTO
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+// Movie is filled in by the movie lookup when reading and is never stored.
+type WatchlistItemDTO struct {
+	Id      bson.ObjectID `bson:"_id,omitempty"`
+	UserId  bson.ObjectID `bson:"user_id"`
+	ImdbId  string        `bson:"imdb_id"`
+	AddedAt time.Time     `bson:"added_at"`
+	Movie   *MovieDTO     `bson:"movie,omitempty"`
+}
+
+type WatchHistoryEntryDTO struct {
+	Id        bson.ObjectID `bson:"_id,omitempty"`
+	UserId    bson.ObjectID `bson:"user_id"`
+	ImdbId    string        `bson:"imdb_id"`
+	WatchedAt time.Time     `bson:"watched_at"`
+	Rating    *int          `bson:"rating"`
+	CreatedAt time.Time     `bson:"created_at"`
+	Movie     *MovieDTO     `bson:"movie,omitempty"`
+}
+
+func FromWatchlistItemCoreToDTO(input *domain.WatchlistItem) (*WatchlistItemDTO, error) {
+	userOID, err := bson.ObjectIDFromHex(input.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WatchlistItemDTO{
+		UserId:  userOID,
+		ImdbId:  input.ImdbId,
+		AddedAt: input.AddedAt,
+	}, nil
+}
+
+func FromWatchlistItemDTOToCore(input *WatchlistItemDTO) *domain.WatchlistItem {
+	item := &domain.WatchlistItem{
+		Id:      input.Id.Hex(),
+		UserId:  input.UserId.Hex(),
+		ImdbId:  input.ImdbId,
+		AddedAt: input.AddedAt,
+	}
+
+	if input.Movie != nil {
+		item.Movie = FromMovieDTOToCore(input.Movie)
+	}
+
+	return item
+}
+
+func FromWatchHistoryEntryCoreToDTO(input *domain.WatchHistoryEntry) (*WatchHistoryEntryDTO, error) {
+	userOID, err := bson.ObjectIDFromHex(input.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WatchHistoryEntryDTO{
+		UserId:    userOID,
+		ImdbId:    input.ImdbId,
+		WatchedAt: input.WatchedAt,
+		Rating:    input.Rating,
+		CreatedAt: input.CreatedAt,
+	}, nil
+}
+
+func FromWatchHistoryEntryDTOToCore(input *WatchHistoryEntryDTO) *domain.WatchHistoryEntry {
+	entry := &domain.WatchHistoryEntry{
+		Id:        input.Id.Hex(),
+		UserId:    input.UserId.Hex(),
+		ImdbId:    input.ImdbId,
+		WatchedAt: input.WatchedAt,
+		Rating:    input.Rating,
+		CreatedAt: input.CreatedAt,
+	}
+
+	if input.Movie != nil {
+		entry.Movie = FromMovieDTOToCore(input.Movie)
+	}
+
+	return entry
+}
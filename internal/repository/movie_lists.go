@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository/mongoDTO"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"slices"
+	"time"
+)
+
+type MovieListRepository interface {
+	CreateMovieList(ctx context.Context, list *domain.MovieList) error
+	GetMovieList(ctx context.Context, ownerId, id string) (*domain.MovieList, error)
+	GetMovieListBySlug(ctx context.Context, slug string) (*domain.MovieList, error)
+	GetMovieListsByOwner(ctx context.Context, ownerId string, publicOnly bool) ([]domain.MovieList, error)
+	GetPublicMovieLists(ctx context.Context, offset, limit int64) ([]domain.MovieList, error)
+	GetPublicMovieListsByCursor(ctx context.Context, cursor *domain.Cursor, limit int64) ([]domain.MovieList, error)
+	CountPublicMovieLists(ctx context.Context) (int64, error)
+	UpdateMovieList(ctx context.Context, list *domain.MovieList) error
+	DeleteMovieList(ctx context.Context, ownerId, id string) error
+	DeleteMovieListsByOwner(ctx context.Context, ownerId string) error
+	RemoveMovieFromLists(ctx context.Context, imdbId string) error
+}
+
+type movieListRepository struct {
+	collection *mongo.Collection
+}
+
+func (m *movieListRepository) CreateMovieList(ctx context.Context, list *domain.MovieList) error {
+	dto, err := mongoDTO.FromMovieListCoreToDTO(list)
+	if err != nil {
+		return err
+	}
+
+	result, err := m.collection.InsertOne(ctx, dto)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(bson.ObjectID); ok {
+		list.Id = oid.Hex()
+	}
+
+	return nil
+}
+
+func (m *movieListRepository) GetMovieList(ctx context.Context, ownerId, id string) (*domain.MovieList, error) {
+	ownerOID, err := bson.ObjectIDFromHex(ownerId)
+	if err != nil {
+		return nil, ErrRecordNotFound
+	}
+
+	listOID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrRecordNotFound
+	}
+
+	return m.findOne(ctx, bson.M{"_id": listOID, "owner_id": ownerOID})
+}
+
+func (m *movieListRepository) GetMovieListBySlug(ctx context.Context, slug string) (*domain.MovieList, error) {
+	return m.findOne(ctx, bson.M{"slug": slug})
+}
+
+func (m *movieListRepository) findOne(ctx context.Context, filter bson.M) (*domain.MovieList, error) {
+	var dto mongoDTO.MovieListDTO
+	if err := m.collection.FindOne(ctx, filter).Decode(&dto); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return mongoDTO.FromMovieListDTOToCore(&dto), nil
+}
+
+func (m *movieListRepository) GetMovieListsByOwner(ctx context.Context, ownerId string, publicOnly bool) ([]domain.MovieList, error) {
+	oid, err := bson.ObjectIDFromHex(ownerId)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"owner_id": oid}
+	if publicOnly {
+		filter["visibility"] = string(domain.ListVisibilityPublic)
+	}
+
+	return m.find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}))
+}
+
+func (m *movieListRepository) GetPublicMovieLists(ctx context.Context, offset, limit int64) ([]domain.MovieList, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}).
+		SetSkip(offset).
+		SetLimit(limit)
+
+	return m.find(ctx, bson.M{"visibility": string(domain.ListVisibilityPublic)}, opts)
+}
+
+// GetPublicMovieListsByCursor returns up to limit public lists, newest first,
+// after cursor or from the start when cursor is nil.
+func (m *movieListRepository) GetPublicMovieListsByCursor(ctx context.Context, cursor *domain.Cursor, limit int64) ([]domain.MovieList, error) {
+	query := bson.M{"visibility": string(domain.ListVisibilityPublic)}
+	sort := bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}
+	if cursor != nil {
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.Key)
+		if err != nil {
+			return nil, fmt.Errorf("cursor key: %w", err)
+		}
+
+		after, err := keysetFilter("created_at", -1, createdAt, cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = bson.M{"$and": bson.A{query, after}}
+
+		if cursor.Backward {
+			sort = reverseSort(sort)
+		}
+	}
+
+	lists, err := m.find(ctx, query, options.Find().SetSort(sort).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(lists)
+	}
+
+	return lists, nil
+}
+
+func (m *movieListRepository) CountPublicMovieLists(ctx context.Context) (int64, error) {
+	return m.collection.CountDocuments(ctx, bson.M{"visibility": string(domain.ListVisibilityPublic)})
+}
+
+func (m *movieListRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptionsBuilder) ([]domain.MovieList, error) {
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var dtos []mongoDTO.MovieListDTO
+	if err := cursor.All(ctx, &dtos); err != nil {
+		return nil, err
+	}
+
+	lists := make([]domain.MovieList, len(dtos))
+	for i := range dtos {
+		lists[i] = *mongoDTO.FromMovieListDTOToCore(&dtos[i])
+	}
+
+	return lists, nil
+}
+
+// UpdateMovieList saves the list, items included, if nobody else changed it
+// since it was read.
+func (m *movieListRepository) UpdateMovieList(ctx context.Context, list *domain.MovieList) error {
+	oid, err := bson.ObjectIDFromHex(list.Id)
+	if err != nil {
+		return ErrRecordNotFound
+	}
+
+	filter := bson.M{
+		"_id":     oid,
+		"version": versionFilter(list.Version),
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"title":       list.Title,
+			"description": list.Description,
+			"visibility":  string(list.Visibility),
+			"items":       mongoDTO.FromMovieListItemsCoreToDTO(list.Items),
+			"updated_at":  list.UpdatedAt,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return versionMismatch(ctx, m.collection, bson.M{"_id": oid})
+	}
+
+	list.Version++
+	return nil
+}
+
+func (m *movieListRepository) DeleteMovieList(ctx context.Context, ownerId, id string) error {
+	ownerOID, err := bson.ObjectIDFromHex(ownerId)
+	if err != nil {
+		return ErrRecordNotFound
+	}
+
+	listOID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrRecordNotFound
+	}
+
+	result, err := m.collection.DeleteOne(ctx, bson.M{"_id": listOID, "owner_id": ownerOID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (m *movieListRepository) DeleteMovieListsByOwner(ctx context.Context, ownerId string) error {
+	oid, err := bson.ObjectIDFromHex(ownerId)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.collection.DeleteMany(ctx, bson.M{"owner_id": oid})
+	return err
+}
+
+// RemoveMovieFromLists takes a deleted movie off every list. The version is
+// bumped so a concurrent edit made from the old items fails instead of putting
+// the movie back.
+func (m *movieListRepository) RemoveMovieFromLists(ctx context.Context, imdbId string) error {
+	_, err := m.collection.UpdateMany(ctx,
+		bson.M{"items.imdb_id": imdbId},
+		bson.M{
+			"$pull": bson.M{"items": bson.M{"imdb_id": imdbId}},
+			"$set":  bson.M{"updated_at": time.Now()},
+			"$inc":  bson.M{"version": 1},
+		},
+	)
+	return err
+}
+
+func NewMovieListRepository(database *mongo.Database, collectionName string) MovieListRepository {
+	return &movieListRepository{
+		collection: database.Collection(collectionName),
+	}
+}
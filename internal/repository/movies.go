@@ -9,15 +9,30 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type MovieRepository interface {
 	CreateMovie(ctx context.Context, movie *domain.Movie) error
+	CreateMovieIfNotExists(ctx context.Context, movie *domain.Movie) (bool, error)
 	GetMovie(ctx context.Context, imdbId string) (*domain.Movie, error)
-	GetMovies(ctx context.Context, offset, limit int64) ([]domain.Movie, error)
+	GetMoviesByImdbIds(ctx context.Context, imdbIds []string) ([]domain.Movie, error)
+	GetMovies(ctx context.Context, filter *domain.MovieFilter, offset, limit int64) ([]domain.Movie, error)
+	GetMoviesByCursor(ctx context.Context, filter *domain.MovieFilter, cursor *domain.Cursor, limit int64) ([]domain.Movie, error)
 	GetRecommendedMovies(ctx context.Context, genres []string, limit int64) ([]domain.Movie, error)
-	UpdateReview(ctx context.Context, imdbId string, adminReview string, ranking *domain.Ranking) error
-	CountMovies(ctx context.Context) (int64, error)
+	UpdateReview(ctx context.Context, movie *domain.Movie) error
+	UpdateMovie(ctx context.Context, movie *domain.Movie) error
+	DeleteMovie(ctx context.Context, imdbId string) error
+	CountMovies(ctx context.Context, filter *domain.MovieFilter) (int64, error)
+	RenameGenre(ctx context.Context, genre *domain.Genre) error
+	ReassignGenre(ctx context.Context, fromId int, to *domain.Genre) error
+	CountMoviesByGenre(ctx context.Context, genreId int) (int64, error)
+	RenameRanking(ctx context.Context, ranking *domain.Ranking) error
+	ReassignRanking(ctx context.Context, fromValue int, to *domain.Ranking) error
+	CountMoviesByRanking(ctx context.Context, rankingValue int) (int64, error)
 }
 
 type movieRepository struct {
@@ -32,7 +47,12 @@ func (m *movieRepository) CreateMovie(ctx context.Context, movie *domain.Movie)
 
 	result, err := m.collection.InsertOne(ctx, dto)
 	if err != nil {
-		return err
+		switch {
+		case m.isDuplicateImdbIdError(err):
+			return ErrDuplicateImdb
+		default:
+			return err
+		}
 	}
 
 	if oid, ok := result.InsertedID.(bson.ObjectID); ok {
@@ -42,6 +62,28 @@ func (m *movieRepository) CreateMovie(ctx context.Context, movie *domain.Movie)
 	return nil
 }
 
+func (m *movieRepository) CreateMovieIfNotExists(ctx context.Context, movie *domain.Movie) (bool, error) {
+	dto, err := mongoDTO.FromMovieCoreToDTO(movie)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := m.collection.UpdateOne(ctx,
+		bson.M{"imdb_id": dto.ImdbId},
+		bson.M{"$setOnInsert": dto},
+		options.UpdateOne().SetUpsert(true),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if oid, ok := result.UpsertedID.(bson.ObjectID); ok {
+		movie.Id = oid.Hex()
+	}
+
+	return result.UpsertedCount > 0, nil
+}
+
 func (m *movieRepository) GetMovie(ctx context.Context, imdbId string) (*domain.Movie, error) {
 	var dto mongoDTO.MovieDTO
 
@@ -56,8 +98,51 @@ func (m *movieRepository) GetMovie(ctx context.Context, imdbId string) (*domain.
 	return mongoDTO.FromMovieDTOToCore(&dto), nil
 }
 
-func (m *movieRepository) GetMovies(ctx context.Context, offset, limit int64) ([]domain.Movie, error) {
-	cursor, err := m.collection.Find(ctx, bson.M{}, options.Find().SetSkip(offset).SetLimit(limit))
+// GetMoviesByImdbIds returns the movies that exist among imdbIds, in no
+// particular order.
+func (m *movieRepository) GetMoviesByImdbIds(ctx context.Context, imdbIds []string) ([]domain.Movie, error) {
+	if len(imdbIds) == 0 {
+		return []domain.Movie{}, nil
+	}
+
+	return m.findMovies(ctx, bson.M{"imdb_id": bson.M{"$in": imdbIds}}, options.Find())
+}
+
+func (m *movieRepository) GetMovies(ctx context.Context, filter *domain.MovieFilter, offset, limit int64) ([]domain.Movie, error) {
+	opts := options.Find().SetSort(movieSort(filter)).SetSkip(offset).SetLimit(limit)
+	return m.findMovies(ctx, movieFilter(filter), opts)
+}
+
+// GetMoviesByCursor returns up to limit movies after cursor, or the first ones
+// when cursor is nil. Backward pages are returned in listing order too.
+func (m *movieRepository) GetMoviesByCursor(ctx context.Context, filter *domain.MovieFilter, cursor *domain.Cursor, limit int64) ([]domain.Movie, error) {
+	query, sort := movieFilter(filter), movieSort(filter)
+	if cursor != nil {
+		after, err := movieKeysetFilter(filter, cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = bson.M{"$and": bson.A{query, after}}
+
+		if cursor.Backward {
+			sort = reverseSort(sort)
+		}
+	}
+
+	movies, err := m.findMovies(ctx, query, options.Find().SetSort(sort).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(movies)
+	}
+
+	return movies, nil
+}
+
+func (m *movieRepository) findMovies(ctx context.Context, query bson.M, opts *options.FindOptionsBuilder) ([]domain.Movie, error) {
+	cursor, err := m.collection.Find(ctx, query, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -110,16 +195,221 @@ func (m *movieRepository) GetRecommendedMovies(ctx context.Context, genres []str
 	return movies, nil
 }
 
-func (m *movieRepository) UpdateReview(ctx context.Context, imdbId string, adminReview string, ranking *domain.Ranking) error {
-	_, err := m.collection.UpdateOne(ctx, bson.M{"imdb_id": imdbId}, bson.M{"$set": bson.M{
-		"admin_review": adminReview,
-		"ranking":      ranking,
-	}})
+func (m *movieRepository) UpdateReview(ctx context.Context, movie *domain.Movie) error {
+	filter := bson.M{
+		"imdb_id": movie.ImdbId,
+		"version": versionFilter(movie.Version),
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"admin_review": movie.AdminReview,
+			"ranking":      mongoDTO.FromRankingCoreToDTO(&movie.Ranking),
+			"updated_at":   movie.UpdatedAt,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return versionMismatch(ctx, m.collection, bson.M{"imdb_id": movie.ImdbId})
+	}
+
+	movie.Version++
+	return nil
+}
+
+func (m *movieRepository) UpdateMovie(ctx context.Context, movie *domain.Movie) error {
+	dto, err := mongoDTO.FromMovieCoreToDTO(movie)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"imdb_id": movie.ImdbId,
+		"version": versionFilter(movie.Version),
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"title":        dto.Title,
+			"poster_path":  dto.PosterPath,
+			"youtube_id":   dto.YoutubeId,
+			"genre":        dto.Genre,
+			"admin_review": dto.AdminReview,
+			"ranking":      dto.Ranking,
+			"updated_at":   dto.UpdatedAt,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return versionMismatch(ctx, m.collection, bson.M{"imdb_id": movie.ImdbId})
+	}
+
+	movie.Version++
+	return nil
+}
+
+func (m *movieRepository) DeleteMovie(ctx context.Context, imdbId string) error {
+	result, err := m.collection.DeleteOne(ctx, bson.M{"imdb_id": imdbId})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (m *movieRepository) CountMovies(ctx context.Context, filter *domain.MovieFilter) (int64, error) {
+	return m.collection.CountDocuments(ctx, movieFilter(filter))
+}
+
+func movieFilter(filter *domain.MovieFilter) bson.M {
+	query := bson.M{}
+	if filter == nil {
+		return query
+	}
+
+	if filter.Query != "" {
+		query["$text"] = bson.M{"$search": filter.Query}
+	}
+
+	if filter.GenreId != nil {
+		query["genre.genre_id"] = *filter.GenreId
+	}
+
+	if filter.RankingValue != nil {
+		query["ranking.ranking_value"] = *filter.RankingValue
+	}
+
+	createdAt := bson.M{}
+	if filter.CreatedAfter != nil {
+		createdAt["$gt"] = *filter.CreatedAfter
+	}
+	if filter.CreatedBefore != nil {
+		createdAt["$lt"] = *filter.CreatedBefore
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+
+	return query
+}
+
+// movieSort always ends on _id so pages stay stable when the sort key ties.
+func movieSort(filter *domain.MovieFilter) bson.D {
+	var sort bson.D
+	if filter != nil {
+		switch filter.Sort {
+		case domain.MovieSortTitle:
+			sort = bson.D{{Key: "title", Value: 1}}
+		case domain.MovieSortNewest:
+			sort = bson.D{{Key: "created_at", Value: -1}}
+		case domain.MovieSortRanking:
+			sort = bson.D{{Key: "ranking.ranking_value", Value: 1}}
+		case "":
+			if filter.Query != "" {
+				sort = bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}}
+			}
+		}
+	}
+
+	return append(sort, bson.E{Key: "_id", Value: 1})
+}
+
+// movieKeysetFilter parses the sort key of cursor back into the type of the
+// field it came from. Without an explicit sort the listing is ordered by _id
+// alone; relevance order has no stable key and cannot be paged by cursor.
+func movieKeysetFilter(filter *domain.MovieFilter, cursor *domain.Cursor) (bson.M, error) {
+	switch filter.Sort {
+	case domain.MovieSortTitle:
+		return keysetFilter("title", 1, cursor.Key, cursor)
+	case domain.MovieSortNewest:
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.Key)
+		if err != nil {
+			return nil, fmt.Errorf("cursor key: %w", err)
+		}
+		return keysetFilter("created_at", -1, createdAt, cursor)
+	case domain.MovieSortRanking:
+		rankingValue, err := strconv.Atoi(cursor.Key)
+		if err != nil {
+			return nil, fmt.Errorf("cursor key: %w", err)
+		}
+		return keysetFilter("ranking.ranking_value", 1, rankingValue, cursor)
+	default:
+		return keysetFilter("", 1, nil, cursor)
+	}
+}
+
+func (m *movieRepository) RenameGenre(ctx context.Context, genre *domain.Genre) error {
+	return renameEmbeddedGenre(ctx, m.collection, "genre", genre)
+}
+
+func (m *movieRepository) ReassignGenre(ctx context.Context, fromId int, to *domain.Genre) error {
+	return reassignEmbeddedGenre(ctx, m.collection, "genre", fromId, to)
+}
+
+func (m *movieRepository) CountMoviesByGenre(ctx context.Context, genreId int) (int64, error) {
+	return countEmbeddedGenre(ctx, m.collection, "genre", genreId)
+}
+
+func (m *movieRepository) RenameRanking(ctx context.Context, ranking *domain.Ranking) error {
+	_, err := m.collection.UpdateMany(ctx,
+		bson.M{"ranking.ranking_value": ranking.RankingValue},
+		bson.M{
+			"$set": bson.M{
+				"ranking.ranking_name": ranking.RankingName,
+				"updated_at":           time.Now(),
+			},
+			"$inc": bson.M{"version": 1},
+		},
+	)
 	return err
 }
 
-func (m *movieRepository) CountMovies(ctx context.Context) (int64, error) {
-	return m.collection.CountDocuments(ctx, bson.M{})
+func (m *movieRepository) ReassignRanking(ctx context.Context, fromValue int, to *domain.Ranking) error {
+	_, err := m.collection.UpdateMany(ctx,
+		bson.M{"ranking.ranking_value": fromValue},
+		bson.M{
+			"$set": bson.M{
+				"ranking":    mongoDTO.FromRankingCoreToDTO(to),
+				"updated_at": time.Now(),
+			},
+			"$inc": bson.M{"version": 1},
+		},
+	)
+	return err
+}
+
+func (m *movieRepository) CountMoviesByRanking(ctx context.Context, rankingValue int) (int64, error) {
+	return m.collection.CountDocuments(ctx, bson.M{"ranking.ranking_value": rankingValue})
+}
+
+func (m *movieRepository) isDuplicateImdbIdError(err error) bool {
+	var we mongo.WriteException
+	if errors.As(err, &we) {
+		for _, e := range we.WriteErrors {
+			if e.Code == 11000 || e.Code == 11001 {
+				if strings.Contains(e.Message, "index: imdb_id_1") {
+					return true
+				}
+			}
+		}
+	}
+	return false
 }
 
 func NewMovieRepository(database *mongo.Database, collectionName string) MovieRepository {
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository/mongoDTO"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"time"
+)
+
+type OIDCStateRepository interface {
+	CreateOIDCState(ctx context.Context, state *domain.OIDCState) error
+	ConsumeOIDCState(ctx context.Context, state, provider string) (*domain.OIDCState, error)
+}
+
+type oidcStateRepository struct {
+	collection *mongo.Collection
+}
+
+func (o *oidcStateRepository) CreateOIDCState(ctx context.Context, state *domain.OIDCState) error {
+	_, err := o.collection.InsertOne(ctx, mongoDTO.FromOIDCStateCoreToDTO(state))
+	return err
+}
+
+// ConsumeOIDCState deletes and returns an unexpired state in one operation, so
+// a callback can only be completed once.
+func (o *oidcStateRepository) ConsumeOIDCState(ctx context.Context, state, provider string) (*domain.OIDCState, error) {
+	var dto mongoDTO.OIDCStateDTO
+
+	filter := bson.M{
+		"_id":        state,
+		"provider":   provider,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+
+	if err := o.collection.FindOneAndDelete(ctx, filter).Decode(&dto); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return mongoDTO.FromOIDCStateDTOToCore(&dto), nil
+}
+
+func NewOIDCStateRepository(database *mongo.Database, collectionName string) OIDCStateRepository {
+	return &oidcStateRepository{
+		collection: database.Collection(collectionName),
+	}
+}
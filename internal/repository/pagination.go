@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// keysetFilter matches the rows after cursor in a listing ordered by field in
+// direction (1 or -1) and then by _id ascending. A backward cursor matches the
+// rows before it instead. An empty field orders by _id alone.
+func keysetFilter(field string, direction int, key any, cursor *domain.Cursor) (bson.M, error) {
+	id, err := bson.ObjectIDFromHex(cursor.Id)
+	if err != nil {
+		return nil, fmt.Errorf("cursor id: %w", err)
+	}
+
+	idOp, keyOp := "$gt", "$gt"
+	if direction < 0 {
+		keyOp = "$lt"
+	}
+	if cursor.Backward {
+		idOp, keyOp = flipComparison(idOp), flipComparison(keyOp)
+	}
+
+	if field == "" {
+		return bson.M{"_id": bson.M{idOp: id}}, nil
+	}
+
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{keyOp: key}},
+		bson.M{field: key, "_id": bson.M{idOp: id}},
+	}}, nil
+}
+
+func flipComparison(op string) string {
+	if op == "$gt" {
+		return "$lt"
+	}
+	return "$gt"
+}
+
+// reverseSort inverts every key of sort, for reading a page backwards.
+func reverseSort(sort bson.D) bson.D {
+	reversed := make(bson.D, len(sort))
+	for i, e := range sort {
+		if direction, ok := e.Value.(int); ok {
+			e.Value = -direction
+		}
+		reversed[i] = e
+	}
+	return reversed
+}
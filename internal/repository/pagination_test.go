@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"reflect"
+	"testing"
+)
+
+func TestKeysetFilter(t *testing.T) {
+	id := bson.NewObjectID()
+
+	tests := []struct {
+		name      string
+		field     string
+		direction int
+		key       any
+		backward  bool
+		want      bson.M
+	}{
+		{
+			name:      "ascending forward",
+			field:     "title",
+			direction: 1,
+			key:       "Alien",
+			want: bson.M{"$or": bson.A{
+				bson.M{"title": bson.M{"$gt": "Alien"}},
+				bson.M{"title": "Alien", "_id": bson.M{"$gt": id}},
+			}},
+		},
+		{
+			name:      "ascending backward",
+			field:     "title",
+			direction: 1,
+			key:       "Alien",
+			backward:  true,
+			want: bson.M{"$or": bson.A{
+				bson.M{"title": bson.M{"$lt": "Alien"}},
+				bson.M{"title": "Alien", "_id": bson.M{"$lt": id}},
+			}},
+		},
+		{
+			// Ties on the sort key always break on _id ascending, so a
+			// descending sort still walks _id upwards when moving forward.
+			name:      "descending forward",
+			field:     "created_at",
+			direction: -1,
+			key:       int64(1700000000),
+			want: bson.M{"$or": bson.A{
+				bson.M{"created_at": bson.M{"$lt": int64(1700000000)}},
+				bson.M{"created_at": int64(1700000000), "_id": bson.M{"$gt": id}},
+			}},
+		},
+		{
+			name:      "descending backward",
+			field:     "created_at",
+			direction: -1,
+			key:       int64(1700000000),
+			backward:  true,
+			want: bson.M{"$or": bson.A{
+				bson.M{"created_at": bson.M{"$gt": int64(1700000000)}},
+				bson.M{"created_at": int64(1700000000), "_id": bson.M{"$lt": id}},
+			}},
+		},
+		{
+			name:      "id only forward",
+			direction: 1,
+			want:      bson.M{"_id": bson.M{"$gt": id}},
+		},
+		{
+			name:      "id only backward",
+			direction: 1,
+			backward:  true,
+			want:      bson.M{"_id": bson.M{"$lt": id}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := keysetFilter(tt.field, tt.direction, tt.key, &domain.Cursor{Id: id.Hex(), Backward: tt.backward})
+			if err != nil {
+				t.Fatalf("keysetFilter: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("keysetFilter = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeysetFilterRejectsInvalidId(t *testing.T) {
+	for _, id := range []string{"", "not-an-object-id", "65f1c0ffee"} {
+		if _, err := keysetFilter("title", 1, "Alien", &domain.Cursor{Id: id}); err == nil {
+			t.Errorf("keysetFilter accepted cursor id %q", id)
+		}
+	}
+}
+
+func TestReverseSort(t *testing.T) {
+	tests := []struct {
+		name string
+		sort bson.D
+		want bson.D
+	}{
+		{
+			name: "single key",
+			sort: bson.D{{Key: "_id", Value: 1}},
+			want: bson.D{{Key: "_id", Value: -1}},
+		},
+		{
+			name: "key with id tie-breaker",
+			sort: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}},
+			want: bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: -1}},
+		},
+		{
+			name: "non numeric value is kept",
+			sort: bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}},
+			want: bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := append(bson.D(nil), tt.sort...)
+			if got := reverseSort(tt.sort); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("reverseSort = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.sort, original) {
+				t.Fatalf("reverseSort modified its input: %v", tt.sort)
+			}
+		})
+	}
+}
@@ -2,20 +2,54 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/repository/mongoDTO"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type RankingRepository interface {
+	CreateRanking(ctx context.Context, ranking *domain.Ranking) error
+	GetRanking(ctx context.Context, rankingValue int) (*domain.Ranking, error)
 	GetRankings(ctx context.Context) ([]domain.Ranking, error)
+	UpdateRanking(ctx context.Context, ranking *domain.Ranking) error
+	DeleteRanking(ctx context.Context, rankingValue int) error
+	UpsertRanking(ctx context.Context, ranking *domain.Ranking) error
 }
 
 type rankingRepository struct {
 	collection *mongo.Collection
 }
 
+func (r *rankingRepository) CreateRanking(ctx context.Context, ranking *domain.Ranking) error {
+	if _, err := r.collection.InsertOne(ctx, mongoDTO.FromRankingCoreToDTO(ranking)); err != nil {
+		switch {
+		case mongo.IsDuplicateKeyError(err):
+			return ErrDuplicateRanking
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *rankingRepository) GetRanking(ctx context.Context, rankingValue int) (*domain.Ranking, error) {
+	var dto mongoDTO.RankingDTO
+
+	if err := r.collection.FindOne(ctx, bson.M{"ranking_value": rankingValue}).Decode(&dto); err != nil {
+		switch {
+		case errors.Is(err, mongo.ErrNoDocuments):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return mongoDTO.FromRankingDTOToCore(&dto), nil
+}
+
 func (r *rankingRepository) GetRankings(ctx context.Context) ([]domain.Ranking, error) {
 	cursor, err := r.collection.Find(ctx, bson.M{})
 	if err != nil {
@@ -36,6 +70,45 @@ func (r *rankingRepository) GetRankings(ctx context.Context) ([]domain.Ranking,
 	return rankings, nil
 }
 
+func (r *rankingRepository) UpdateRanking(ctx context.Context, ranking *domain.Ranking) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"ranking_value": ranking.RankingValue},
+		bson.M{"$set": bson.M{"ranking_name": ranking.RankingName}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *rankingRepository) DeleteRanking(ctx context.Context, rankingValue int) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"ranking_value": rankingValue})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *rankingRepository) UpsertRanking(ctx context.Context, ranking *domain.Ranking) error {
+	dto := mongoDTO.FromRankingCoreToDTO(ranking)
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"ranking_value": dto.RankingValue},
+		bson.M{"$set": dto},
+		options.UpdateOne().SetUpsert(true),
+	)
+	return err
+}
+
 func NewRankingsRepository(database *mongo.Database, collectionName string) RankingRepository {
 	return &rankingRepository{
 		collection: database.Collection(collectionName),
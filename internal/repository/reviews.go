@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository/mongoDTO"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"slices"
+	"time"
+)
+
+type ReviewRepository interface {
+	CreateReview(ctx context.Context, review *domain.Review) error
+	GetReview(ctx context.Context, imdbId, userId string) (*domain.Review, error)
+	GetReviewsByCursor(ctx context.Context, imdbId string, cursor *domain.Cursor, limit int64) ([]domain.Review, error)
+	GetReviewsByUserId(ctx context.Context, userId string) ([]domain.Review, error)
+	UpdateReview(ctx context.Context, review *domain.Review) (*domain.Review, error)
+	DeleteReview(ctx context.Context, imdbId, userId string) (*domain.Review, error)
+	DeleteReviewsByImdbId(ctx context.Context, imdbId string) error
+	CountReviews(ctx context.Context, imdbId string) (int64, error)
+	RefreshMovieRating(ctx context.Context, imdbId string) error
+}
+
+type reviewRepository struct {
+	collection      *mongo.Collection
+	movieCollection *mongo.Collection
+}
+
+func (r *reviewRepository) CreateReview(ctx context.Context, review *domain.Review) error {
+	dto, err := mongoDTO.FromReviewCoreToDTO(review)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.InsertOne(ctx, dto)
+	if err != nil {
+		switch {
+		case mongo.IsDuplicateKeyError(err):
+			return ErrDuplicateReview
+		default:
+			return err
+		}
+	}
+
+	if oid, ok := result.InsertedID.(bson.ObjectID); ok {
+		review.Id = oid.Hex()
+	}
+
+	return nil
+}
+
+func (r *reviewRepository) GetReview(ctx context.Context, imdbId, userId string) (*domain.Review, error) {
+	oid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, ErrRecordNotFound
+	}
+
+	var dto mongoDTO.ReviewDTO
+	if err := r.collection.FindOne(ctx, bson.M{"imdb_id": imdbId, "user_id": oid}).Decode(&dto); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return mongoDTO.FromReviewDTOToCore(&dto), nil
+}
+
+// GetReviewsByCursor returns up to limit reviews of a movie, newest first,
+// after cursor or from the start when cursor is nil.
+func (r *reviewRepository) GetReviewsByCursor(ctx context.Context, imdbId string, cursor *domain.Cursor, limit int64) ([]domain.Review, error) {
+	query, sort := bson.M{"imdb_id": imdbId}, bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}
+	if cursor != nil {
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.Key)
+		if err != nil {
+			return nil, fmt.Errorf("cursor key: %w", err)
+		}
+
+		after, err := keysetFilter("created_at", -1, createdAt, cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = bson.M{"$and": bson.A{query, after}}
+
+		if cursor.Backward {
+			sort = reverseSort(sort)
+		}
+	}
+
+	reviews, err := r.findReviews(ctx, query, options.Find().SetSort(sort).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(reviews)
+	}
+
+	return reviews, nil
+}
+
+func (r *reviewRepository) GetReviewsByUserId(ctx context.Context, userId string) ([]domain.Review, error) {
+	oid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.findReviews(ctx, bson.M{"user_id": oid}, options.Find())
+}
+
+func (r *reviewRepository) findReviews(ctx context.Context, query bson.M, opts *options.FindOptionsBuilder) ([]domain.Review, error) {
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var dtos []mongoDTO.ReviewDTO
+	if err := cursor.All(ctx, &dtos); err != nil {
+		return nil, err
+	}
+
+	reviews := make([]domain.Review, len(dtos))
+	for i := range dtos {
+		reviews[i] = *mongoDTO.FromReviewDTOToCore(&dtos[i])
+	}
+
+	return reviews, nil
+}
+
+// UpdateReview writes the rating and body of the caller's review and returns
+// the review as it was before, so the caller can work out the rating delta
+// without a separate, racy read.
+func (r *reviewRepository) UpdateReview(ctx context.Context, review *domain.Review) (*domain.Review, error) {
+	oid, err := bson.ObjectIDFromHex(review.UserId)
+	if err != nil {
+		return nil, ErrRecordNotFound
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"rating":     review.Rating,
+			"body":       review.Body,
+			"updated_at": review.UpdatedAt,
+		},
+	}
+
+	var dto mongoDTO.ReviewDTO
+	if err := r.collection.FindOneAndUpdate(ctx, bson.M{"imdb_id": review.ImdbId, "user_id": oid}, update).Decode(&dto); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return mongoDTO.FromReviewDTOToCore(&dto), nil
+}
+
+// DeleteReview removes the review and returns what was deleted.
+func (r *reviewRepository) DeleteReview(ctx context.Context, imdbId, userId string) (*domain.Review, error) {
+	oid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, ErrRecordNotFound
+	}
+
+	var dto mongoDTO.ReviewDTO
+	if err := r.collection.FindOneAndDelete(ctx, bson.M{"imdb_id": imdbId, "user_id": oid}).Decode(&dto); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return mongoDTO.FromReviewDTOToCore(&dto), nil
+}
+
+func (r *reviewRepository) DeleteReviewsByImdbId(ctx context.Context, imdbId string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"imdb_id": imdbId})
+	return err
+}
+
+func (r *reviewRepository) CountReviews(ctx context.Context, imdbId string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"imdb_id": imdbId})
+}
+
+// RefreshMovieRating recomputes the rating counters of a movie from its
+// reviews. Recounting instead of applying deltas means a failed or interleaved
+// write can never leave the counters drifting: the next review write repairs
+// them. A movie that no longer exists is left alone.
+func (r *reviewRepository) RefreshMovieRating(ctx context.Context, imdbId string) error {
+	cursor, err := r.movieCollection.Aggregate(ctx, MovieRatingPipeline(bson.M{"imdb_id": imdbId}, r.collection.Name(), r.movieCollection.Name()))
+	if err != nil {
+		return err
+	}
+	return cursor.Close(ctx)
+}
+
+// MovieRatingPipeline recounts rating_sum, rating_count and average_rating for
+// the movies matching filter and merges them back into the movie collection.
+func MovieRatingPipeline(filter bson.M, reviewCollection, movieCollection string) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         reviewCollection,
+			"localField":   "imdb_id",
+			"foreignField": "imdb_id",
+			"pipeline": bson.A{
+				bson.M{"$group": bson.M{"_id": nil, "sum": bson.M{"$sum": "$rating"}, "count": bson.M{"$sum": 1}}},
+			},
+			"as": "ratings",
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"rating_sum":   bson.M{"$toLong": bson.M{"$ifNull": bson.A{bson.M{"$first": "$ratings.sum"}, 0}}},
+			"rating_count": bson.M{"$toLong": bson.M{"$ifNull": bson.A{bson.M{"$first": "$ratings.count"}, 0}}},
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"average_rating": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{"$rating_count", 0}},
+				bson.M{"$round": bson.A{bson.M{"$divide": bson.A{"$rating_sum", "$rating_count"}}, 2}},
+				0.0,
+			}},
+		}}},
+		{{Key: "$merge", Value: bson.M{"into": movieCollection, "on": "_id", "whenMatched": "merge", "whenNotMatched": "discard"}}},
+	}
+}
+
+func NewReviewRepository(database *mongo.Database, collectionName, movieCollectionName string) ReviewRepository {
+	return &reviewRepository{
+		collection:      database.Collection(collectionName),
+		movieCollection: database.Collection(movieCollectionName),
+	}
+}
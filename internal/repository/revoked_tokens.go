@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository/mongoDTO"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"time"
+)
+
+type RevokedTokenRepository interface {
+	RevokeTokens(ctx context.Context, tokens []domain.RevokedToken) error
+	GetRevokedTokensSince(ctx context.Context, since time.Time) ([]domain.RevokedToken, error)
+}
+
+type revokedTokenRepository struct {
+	collection *mongo.Collection
+}
+
+// RevokeTokens upserts by jti so revoking an already revoked token is a no-op.
+func (r *revokedTokenRepository) RevokeTokens(ctx context.Context, tokens []domain.RevokedToken) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, len(tokens))
+	for i := range tokens {
+		dto := mongoDTO.FromRevokedTokenCoreToDTO(&tokens[i])
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": dto.Jti}).
+			SetUpdate(bson.M{"$setOnInsert": dto}).
+			SetUpsert(true)
+	}
+
+	_, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (r *revokedTokenRepository) GetRevokedTokensSince(ctx context.Context, since time.Time) ([]domain.RevokedToken, error) {
+	filter := bson.M{
+		"revoked_at": bson.M{"$gte": since},
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var dtos []mongoDTO.RevokedTokenDTO
+	if err := cursor.All(ctx, &dtos); err != nil {
+		return nil, err
+	}
+
+	tokens := make([]domain.RevokedToken, len(dtos))
+	for i := range dtos {
+		tokens[i] = *mongoDTO.FromRevokedTokenDTOToCore(&dtos[i])
+	}
+
+	return tokens, nil
+}
+
+func NewRevokedTokenRepository(database *mongo.Database, collectionName string) RevokedTokenRepository {
+	return &revokedTokenRepository{
+		collection: database.Collection(collectionName),
+	}
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository/mongoDTO"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type RoleRepository interface {
+	GetRole(ctx context.Context, name string) (*domain.Role, error)
+	GetRoles(ctx context.Context) ([]domain.Role, error)
+	UpdateRolePermissions(ctx context.Context, role *domain.Role) error
+}
+
+type roleRepository struct {
+	collection *mongo.Collection
+}
+
+func (r *roleRepository) GetRole(ctx context.Context, name string) (*domain.Role, error) {
+	var dto mongoDTO.RoleDTO
+
+	if err := r.collection.FindOne(ctx, bson.M{"name": name}).Decode(&dto); err != nil {
+		switch {
+		case errors.Is(err, mongo.ErrNoDocuments):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return mongoDTO.FromRoleDTOToCore(&dto), nil
+}
+
+func (r *roleRepository) GetRoles(ctx context.Context) ([]domain.Role, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var dtos []mongoDTO.RoleDTO
+	if err := cursor.All(ctx, &dtos); err != nil {
+		return nil, err
+	}
+
+	roles := make([]domain.Role, len(dtos))
+	for i := range dtos {
+		roles[i] = *mongoDTO.FromRoleDTOToCore(&dtos[i])
+	}
+
+	return roles, nil
+}
+
+func (r *roleRepository) UpdateRolePermissions(ctx context.Context, role *domain.Role) error {
+	dto := mongoDTO.FromRoleCoreToDTO(role)
+	result, err := r.collection.UpdateOne(ctx, bson.M{"name": dto.Name}, bson.M{
+		"$set": bson.M{
+			"permissions": dto.Permissions,
+			"updated_at":  dto.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func NewRoleRepository(database *mongo.Database, collectionName string) RoleRepository {
+	return &roleRepository{
+		collection: database.Collection(collectionName),
+	}
+}
@@ -7,19 +7,29 @@ import (
 	"github.com/saleh-ghazimoradi/Projectopher/internal/repository/mongoDTO"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"time"
 )
 
 type TokenRepository interface {
 	CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error
-	GetValidRefreshToken(ctx context.Context, token string) (*domain.RefreshToken, error)
-	DeleteRefreshToken(ctx context.Context, token string) error
-	DeleteRefreshTokenById(ctx context.Context, id string) error
+	RotateRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	DeleteRefreshTokenFamily(ctx context.Context, familyId string) error
+	GetActiveRefreshTokensByUserId(ctx context.Context, userId string) ([]domain.RefreshToken, error)
+	GetLiveAccessTokensByUserId(ctx context.Context, userId string) ([]domain.RefreshToken, error)
+	GetLiveAccessTokensByFamilyId(ctx context.Context, familyId string) ([]domain.RefreshToken, error)
+	DeleteUserRefreshTokenFamily(ctx context.Context, userId, familyId string) error
 	DeleteExpired(ctx context.Context) error
+	DeleteRefreshTokensByUserId(ctx context.Context, userId string) error
+	CreatePasswordResetToken(ctx context.Context, token *domain.PasswordResetToken) error
+	ConsumePasswordResetToken(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error)
+	DeletePasswordResetTokensByUserId(ctx context.Context, userId string) error
 }
 
 type tokenRepository struct {
-	collection *mongo.Collection
+	collection      *mongo.Collection
+	resetCollection *mongo.Collection
 }
 
 func (t *tokenRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
@@ -40,13 +50,22 @@ func (t *tokenRepository) CreateRefreshToken(ctx context.Context, token *domain.
 	return nil
 }
 
-func (t *tokenRepository) GetValidRefreshToken(ctx context.Context, token string) (*domain.RefreshToken, error) {
+// RotateRefreshToken marks an unexpired, not yet rotated token as rotated and
+// returns it. Doing the check and the update in one operation guarantees a
+// token can only be exchanged once.
+func (t *tokenRepository) RotateRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
 	var dto mongoDTO.RefreshTokenDTO
 
-	err := t.collection.FindOne(ctx, bson.M{
-		"token":      token,
-		"expires_at": bson.M{"$gt": time.Now()},
-	}).Decode(&dto)
+	now := time.Now()
+	err := t.collection.FindOneAndUpdate(ctx,
+		bson.M{
+			"token_hash": tokenHash,
+			"rotated_at": nil,
+			"expires_at": bson.M{"$gt": now},
+		},
+		bson.M{"$set": bson.M{"rotated_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&dto)
 
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
@@ -58,17 +77,100 @@ func (t *tokenRepository) GetValidRefreshToken(ctx context.Context, token string
 	return mongoDTO.FromRefreshTokenDTOToCore(&dto), nil
 }
 
-func (t *tokenRepository) DeleteRefreshToken(ctx context.Context, token string) error {
-	_, err := t.collection.DeleteOne(ctx, bson.M{"token": token})
-	return err
+func (t *tokenRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var dto mongoDTO.RefreshTokenDTO
+
+	if err := t.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&dto); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return mongoDTO.FromRefreshTokenDTOToCore(&dto), nil
 }
 
-func (t *tokenRepository) DeleteRefreshTokenById(ctx context.Context, id string) error {
-	oid, _ := t.oId(id)
-	_, err := t.collection.DeleteOne(ctx, bson.M{"_id": oid})
+func (t *tokenRepository) DeleteRefreshTokenFamily(ctx context.Context, familyId string) error {
+	_, err := t.collection.DeleteMany(ctx, bson.M{"family_id": familyId})
 	return err
 }
 
+// GetActiveRefreshTokensByUserId returns the current token of every live
+// family, i.e. one entry per session, most recently used first.
+func (t *tokenRepository) GetActiveRefreshTokensByUserId(ctx context.Context, userId string) ([]domain.RefreshToken, error) {
+	oid, err := t.oId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"user_id":    oid,
+		"rotated_at": nil,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+
+	return t.find(ctx, filter, options.Find().SetSort(bson.D{{Key: "last_used_at", Value: -1}}))
+}
+
+// GetLiveAccessTokensByUserId returns every token record, rotated or not,
+// whose paired access token has not expired yet.
+func (t *tokenRepository) GetLiveAccessTokensByUserId(ctx context.Context, userId string) ([]domain.RefreshToken, error) {
+	oid, err := t.oId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.find(ctx, bson.M{
+		"user_id":           oid,
+		"access_expires_at": bson.M{"$gt": time.Now()},
+	})
+}
+
+func (t *tokenRepository) GetLiveAccessTokensByFamilyId(ctx context.Context, familyId string) ([]domain.RefreshToken, error) {
+	return t.find(ctx, bson.M{
+		"family_id":         familyId,
+		"access_expires_at": bson.M{"$gt": time.Now()},
+	})
+}
+
+func (t *tokenRepository) find(ctx context.Context, filter bson.M, opts ...options.Lister[options.FindOptions]) ([]domain.RefreshToken, error) {
+	cursor, err := t.collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var dtos []mongoDTO.RefreshTokenDTO
+	if err := cursor.All(ctx, &dtos); err != nil {
+		return nil, err
+	}
+
+	tokens := make([]domain.RefreshToken, len(dtos))
+	for i := range dtos {
+		tokens[i] = *mongoDTO.FromRefreshTokenDTOToCore(&dtos[i])
+	}
+
+	return tokens, nil
+}
+
+func (t *tokenRepository) DeleteUserRefreshTokenFamily(ctx context.Context, userId, familyId string) error {
+	oid, err := t.oId(userId)
+	if err != nil {
+		return ErrRecordNotFound
+	}
+
+	result, err := t.collection.DeleteMany(ctx, bson.M{"user_id": oid, "family_id": familyId})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
 func (t *tokenRepository) DeleteExpired(ctx context.Context) error {
 	_, err := t.collection.DeleteMany(ctx, bson.M{
 		"expires_at": bson.M{"$lte": time.Now()},
@@ -76,13 +178,77 @@ func (t *tokenRepository) DeleteExpired(ctx context.Context) error {
 	return err
 }
 
+func (t *tokenRepository) DeleteRefreshTokensByUserId(ctx context.Context, userId string) error {
+	oid, err := t.oId(userId)
+	if err != nil {
+		return err
+	}
+	_, err = t.collection.DeleteMany(ctx, bson.M{"user_id": oid})
+	return err
+}
+
+func (t *tokenRepository) CreatePasswordResetToken(ctx context.Context, token *domain.PasswordResetToken) error {
+	dto, err := mongoDTO.FromPasswordResetTokenCoreToDTO(token)
+	if err != nil {
+		return err
+	}
+
+	result, err := t.resetCollection.InsertOne(ctx, dto)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(bson.ObjectID); ok {
+		token.Id = oid.Hex()
+	}
+
+	return nil
+}
+
+// ConsumePasswordResetToken marks an unused, unexpired token as used and
+// returns it. The check and the update happen in one operation so a token
+// can only ever be redeemed once.
+func (t *tokenRepository) ConsumePasswordResetToken(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	var dto mongoDTO.PasswordResetTokenDTO
+
+	now := time.Now()
+	err := t.resetCollection.FindOneAndUpdate(ctx,
+		bson.M{
+			"token_hash": tokenHash,
+			"used_at":    nil,
+			"expires_at": bson.M{"$gt": now},
+		},
+		bson.M{"$set": bson.M{"used_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&dto)
+
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return mongoDTO.FromPasswordResetTokenDTOToCore(&dto), nil
+}
+
+func (t *tokenRepository) DeletePasswordResetTokensByUserId(ctx context.Context, userId string) error {
+	oid, err := t.oId(userId)
+	if err != nil {
+		return err
+	}
+	_, err = t.resetCollection.DeleteMany(ctx, bson.M{"user_id": oid})
+	return err
+}
+
 func (t *tokenRepository) oId(id string) (bson.ObjectID, error) {
 	oid, err := bson.ObjectIDFromHex(id)
 	return oid, err
 }
 
-func NewTokenRepository(database *mongo.Database, collectionName string) TokenRepository {
+func NewTokenRepository(database *mongo.Database, collectionName, resetCollectionName string) TokenRepository {
 	return &tokenRepository{
-		collection: database.Collection(collectionName),
+		collection:      database.Collection(collectionName),
+		resetCollection: database.Collection(resetCollectionName),
 	}
 }
@@ -9,18 +9,31 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"slices"
 	"strings"
 )
 
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *domain.User) error
+	CreateUserIfNotExists(ctx context.Context, user *domain.User) (bool, error)
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
 	GetUserById(ctx context.Context, id string) (*domain.User, error)
+	GetUserByIdentity(ctx context.Context, provider, subject string) (*domain.User, error)
 	GetUsers(ctx context.Context, offset, limit int64) ([]domain.User, error)
+	GetUsersByCursor(ctx context.Context, cursor *domain.Cursor, limit int64) ([]domain.User, error)
 	GetUserFavoriteGenres(ctx context.Context, userId string) ([]string, error)
 	UpdateUser(ctx context.Context, user *domain.User) error
+	UpdatePassword(ctx context.Context, user *domain.User) error
+	MarkEmailVerified(ctx context.Context, user *domain.User) error
+	AddUserIdentity(ctx context.Context, id string, identity *domain.UserIdentity) error
+	UpdateMFA(ctx context.Context, user *domain.User) error
+	ConsumeMFAStep(ctx context.Context, id string, step int64) error
+	ConsumeRecoveryCode(ctx context.Context, id string, codeHash string) error
 	DeleteUser(ctx context.Context, id string) error
 	CountUser(ctx context.Context) (int64, error)
+	RenameFavoriteGenre(ctx context.Context, genre *domain.Genre) error
+	ReassignFavoriteGenre(ctx context.Context, fromId int, to *domain.Genre) error
+	CountUsersByFavoriteGenre(ctx context.Context, genreId int) (int64, error)
 }
 
 type userRepository struct {
@@ -50,6 +63,28 @@ func (u *userRepository) CreateUser(ctx context.Context, user *domain.User) erro
 	return nil
 }
 
+func (u *userRepository) CreateUserIfNotExists(ctx context.Context, user *domain.User) (bool, error) {
+	userDTO, err := mongoDTO.FromUserCoreToDTO(user)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := u.collection.UpdateOne(ctx,
+		bson.M{"email": userDTO.Email},
+		bson.M{"$setOnInsert": userDTO},
+		options.UpdateOne().SetUpsert(true),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if oid, ok := result.UpsertedID.(bson.ObjectID); ok {
+		user.Id = oid.Hex()
+	}
+
+	return result.UpsertedCount > 0, nil
+}
+
 func (u *userRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var userDTO mongoDTO.UserDTO
 
@@ -79,8 +114,54 @@ func (u *userRepository) GetUserById(ctx context.Context, id string) (*domain.Us
 	return mongoDTO.FromUserDTOToCore(&userDTO), nil
 }
 
+func (u *userRepository) GetUserByIdentity(ctx context.Context, provider, subject string) (*domain.User, error) {
+	var userDTO mongoDTO.UserDTO
+
+	filter := bson.M{"identities": bson.M{"$elemMatch": bson.M{"provider": provider, "subject": subject}}}
+	if err := u.collection.FindOne(ctx, filter).Decode(&userDTO); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return mongoDTO.FromUserDTOToCore(&userDTO), nil
+}
+
 func (u *userRepository) GetUsers(ctx context.Context, offset, limit int64) ([]domain.User, error) {
-	cursor, err := u.collection.Find(ctx, bson.M{}, options.Find().SetSkip(offset).SetLimit(limit))
+	return u.findUsers(ctx, bson.M{}, options.Find().SetSkip(offset).SetLimit(limit))
+}
+
+// GetUsersByCursor returns up to limit users after cursor in _id order, or the
+// first ones when cursor is nil.
+func (u *userRepository) GetUsersByCursor(ctx context.Context, cursor *domain.Cursor, limit int64) ([]domain.User, error) {
+	query, sort := bson.M{}, bson.D{{Key: "_id", Value: 1}}
+	if cursor != nil {
+		after, err := keysetFilter("", 1, nil, cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = after
+
+		if cursor.Backward {
+			sort = reverseSort(sort)
+		}
+	}
+
+	users, err := u.findUsers(ctx, query, options.Find().SetSort(sort).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(users)
+	}
+
+	return users, nil
+}
+
+func (u *userRepository) findUsers(ctx context.Context, query bson.M, opts *options.FindOptionsBuilder) ([]domain.User, error) {
+	cursor, err := u.collection.Find(ctx, query, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -141,11 +222,42 @@ func (u *userRepository) GetUserFavoriteGenres(ctx context.Context, userId strin
 
 func (u *userRepository) UpdateUser(ctx context.Context, user *domain.User) error {
 	oid, _ := u.oId(user.Id)
+	filter := bson.M{
+		"_id":     oid,
+		"version": versionFilter(user.Version),
+	}
+
 	update := bson.M{
 		"$set": bson.M{
 			"first_name": user.FirstName,
 			"last_name":  user.LastName,
+			"role":       string(user.Role),
+			"updated_at": user.UpdatedAt,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := u.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return versionMismatch(ctx, u.collection, bson.M{"_id": oid})
+	}
+
+	user.Version++
+	return nil
+}
+
+func (u *userRepository) UpdatePassword(ctx context.Context, user *domain.User) error {
+	oid, _ := u.oId(user.Id)
+	update := bson.M{
+		"$set": bson.M{
+			"password":   user.Password,
+			"updated_at": user.UpdatedAt,
 		},
+		"$inc": bson.M{"version": 1},
 	}
 
 	result, err := u.collection.UpdateOne(ctx, bson.M{"_id": oid}, update)
@@ -157,6 +269,127 @@ func (u *userRepository) UpdateUser(ctx context.Context, user *domain.User) erro
 		return ErrRecordNotFound
 	}
 
+	user.Version++
+	return nil
+}
+
+func (u *userRepository) UpdateMFA(ctx context.Context, user *domain.User) error {
+	oid, _ := u.oId(user.Id)
+	update := bson.M{
+		"$set": bson.M{
+			"mfa":        mongoDTO.UserMFADTO(user.MFA),
+			"updated_at": user.UpdatedAt,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := u.collection.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	user.Version++
+	return nil
+}
+
+// ConsumeMFAStep records step as the last accepted TOTP step. It returns
+// ErrRecordNotFound when the step was already used, so a code can't be
+// replayed.
+func (u *userRepository) ConsumeMFAStep(ctx context.Context, id string, step int64) error {
+	oid, _ := u.oId(id)
+	filter := bson.M{
+		"_id":           oid,
+		"mfa.last_step": bson.M{"$lt": step},
+	}
+
+	result, err := u.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"mfa.last_step": step}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (u *userRepository) ConsumeRecoveryCode(ctx context.Context, id string, codeHash string) error {
+	oid, _ := u.oId(id)
+	filter := bson.M{
+		"_id":                oid,
+		"mfa.recovery_codes": codeHash,
+	}
+
+	result, err := u.collection.UpdateOne(ctx, filter, bson.M{"$pull": bson.M{"mfa.recovery_codes": codeHash}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (u *userRepository) MarkEmailVerified(ctx context.Context, user *domain.User) error {
+	oid, _ := u.oId(user.Id)
+	filter := bson.M{
+		"_id":   oid,
+		"email": user.Email,
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"email_verified": true,
+			"verified_at":    user.VerifiedAt,
+			"updated_at":     user.UpdatedAt,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := u.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	user.EmailVerified = true
+	user.Version++
+	return nil
+}
+
+// AddUserIdentity links identity to the user unless the user already has one
+// for the same provider.
+func (u *userRepository) AddUserIdentity(ctx context.Context, id string, identity *domain.UserIdentity) error {
+	oid, _ := u.oId(id)
+	filter := bson.M{
+		"_id":                 oid,
+		"identities.provider": bson.M{"$ne": identity.Provider},
+	}
+
+	update := bson.M{
+		"$push": bson.M{"identities": mongoDTO.UserIdentityDTO(*identity)},
+		"$inc":  bson.M{"version": 1},
+	}
+
+	result, err := u.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrRecordNotFound
+	}
+
 	return nil
 }
 
@@ -173,6 +406,18 @@ func (u *userRepository) CountUser(ctx context.Context) (int64, error) {
 	return u.collection.CountDocuments(ctx, bson.M{})
 }
 
+func (u *userRepository) RenameFavoriteGenre(ctx context.Context, genre *domain.Genre) error {
+	return renameEmbeddedGenre(ctx, u.collection, "favorite_genres", genre)
+}
+
+func (u *userRepository) ReassignFavoriteGenre(ctx context.Context, fromId int, to *domain.Genre) error {
+	return reassignEmbeddedGenre(ctx, u.collection, "favorite_genres", fromId, to)
+}
+
+func (u *userRepository) CountUsersByFavoriteGenre(ctx context.Context, genreId int) (int64, error) {
+	return countEmbeddedGenre(ctx, u.collection, "favorite_genres", genreId)
+}
+
 func (u *userRepository) oId(id string) (bson.ObjectID, error) {
 	oid, err := bson.ObjectIDFromHex(id)
 	return oid, err
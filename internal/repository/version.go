@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// versionFilter matches documents at the given version. Documents written
+// before versioning was introduced have no version field and are treated as
+// version 0.
+func versionFilter(version int64) any {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
+// versionMismatch explains a versioned update that matched nothing: the
+// document identified by filter was either deleted or changed by someone else.
+func versionMismatch(ctx context.Context, collection *mongo.Collection, filter bson.M) error {
+	count, err := collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrRecordNotFound
+	}
+	return ErrEditConflict
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository/mongoDTO"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"slices"
+)
+
+type WatchHistoryRepository interface {
+	CreateWatchHistoryEntry(ctx context.Context, entry *domain.WatchHistoryEntry) error
+	GetWatchHistoryByCursor(ctx context.Context, userId string, cursor *domain.Cursor, limit int64) ([]domain.WatchHistoryEntry, error)
+	CountWatchHistory(ctx context.Context, userId string) (int64, error)
+	DeleteWatchHistoryEntry(ctx context.Context, userId, id string) error
+	DeleteWatchHistoryByUserId(ctx context.Context, userId string) error
+	DeleteWatchHistoryByImdbId(ctx context.Context, imdbId string) error
+}
+
+type watchHistoryRepository struct {
+	collection      *mongo.Collection
+	movieCollection string
+}
+
+func (w *watchHistoryRepository) CreateWatchHistoryEntry(ctx context.Context, entry *domain.WatchHistoryEntry) error {
+	dto, err := mongoDTO.FromWatchHistoryEntryCoreToDTO(entry)
+	if err != nil {
+		return err
+	}
+
+	result, err := w.collection.InsertOne(ctx, dto)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(bson.ObjectID); ok {
+		entry.Id = oid.Hex()
+	}
+
+	return nil
+}
+
+// GetWatchHistoryByCursor returns up to limit entries, most recently watched
+// first, each joined with its movie.
+func (w *watchHistoryRepository) GetWatchHistoryByCursor(ctx context.Context, userId string, cursor *domain.Cursor, limit int64) ([]domain.WatchHistoryEntry, error) {
+	oid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline, err := userKeysetPipeline(oid, "watched_at", cursor, limit, w.movieCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := w.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close(ctx)
+
+	var dtos []mongoDTO.WatchHistoryEntryDTO
+	if err := result.All(ctx, &dtos); err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.WatchHistoryEntry, len(dtos))
+	for i := range dtos {
+		entries[i] = *mongoDTO.FromWatchHistoryEntryDTOToCore(&dtos[i])
+	}
+
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(entries)
+	}
+
+	return entries, nil
+}
+
+func (w *watchHistoryRepository) CountWatchHistory(ctx context.Context, userId string) (int64, error) {
+	oid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return 0, err
+	}
+
+	return w.collection.CountDocuments(ctx, bson.M{"user_id": oid})
+}
+
+func (w *watchHistoryRepository) DeleteWatchHistoryEntry(ctx context.Context, userId, id string) error {
+	userOID, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return ErrRecordNotFound
+	}
+
+	entryOID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrRecordNotFound
+	}
+
+	result, err := w.collection.DeleteOne(ctx, bson.M{"_id": entryOID, "user_id": userOID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (w *watchHistoryRepository) DeleteWatchHistoryByUserId(ctx context.Context, userId string) error {
+	oid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.collection.DeleteMany(ctx, bson.M{"user_id": oid})
+	return err
+}
+
+func (w *watchHistoryRepository) DeleteWatchHistoryByImdbId(ctx context.Context, imdbId string) error {
+	_, err := w.collection.DeleteMany(ctx, bson.M{"imdb_id": imdbId})
+	return err
+}
+
+func NewWatchHistoryRepository(database *mongo.Database, collectionName, movieCollectionName string) WatchHistoryRepository {
+	return &watchHistoryRepository{
+		collection:      database.Collection(collectionName),
+		movieCollection: movieCollectionName,
+	}
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository/mongoDTO"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"slices"
+	"time"
+)
+
+type WatchlistRepository interface {
+	CreateWatchlistItem(ctx context.Context, item *domain.WatchlistItem) error
+	GetWatchlistByCursor(ctx context.Context, userId string, cursor *domain.Cursor, limit int64) ([]domain.WatchlistItem, error)
+	CountWatchlist(ctx context.Context, userId string) (int64, error)
+	DeleteWatchlistItem(ctx context.Context, userId, imdbId string) error
+	DeleteWatchlistByUserId(ctx context.Context, userId string) error
+	DeleteWatchlistByImdbId(ctx context.Context, imdbId string) error
+}
+
+type watchlistRepository struct {
+	collection      *mongo.Collection
+	movieCollection string
+}
+
+func (w *watchlistRepository) CreateWatchlistItem(ctx context.Context, item *domain.WatchlistItem) error {
+	dto, err := mongoDTO.FromWatchlistItemCoreToDTO(item)
+	if err != nil {
+		return err
+	}
+
+	result, err := w.collection.InsertOne(ctx, dto)
+	if err != nil {
+		switch {
+		case mongo.IsDuplicateKeyError(err):
+			return ErrDuplicateWatchlistItem
+		default:
+			return err
+		}
+	}
+
+	if oid, ok := result.InsertedID.(bson.ObjectID); ok {
+		item.Id = oid.Hex()
+	}
+
+	return nil
+}
+
+// GetWatchlistByCursor returns up to limit items, most recently added first,
+// each joined with its movie.
+func (w *watchlistRepository) GetWatchlistByCursor(ctx context.Context, userId string, cursor *domain.Cursor, limit int64) ([]domain.WatchlistItem, error) {
+	oid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline, err := userKeysetPipeline(oid, "added_at", cursor, limit, w.movieCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := w.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close(ctx)
+
+	var dtos []mongoDTO.WatchlistItemDTO
+	if err := result.All(ctx, &dtos); err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.WatchlistItem, len(dtos))
+	for i := range dtos {
+		items[i] = *mongoDTO.FromWatchlistItemDTOToCore(&dtos[i])
+	}
+
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(items)
+	}
+
+	return items, nil
+}
+
+func (w *watchlistRepository) CountWatchlist(ctx context.Context, userId string) (int64, error) {
+	oid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return 0, err
+	}
+
+	return w.collection.CountDocuments(ctx, bson.M{"user_id": oid})
+}
+
+func (w *watchlistRepository) DeleteWatchlistItem(ctx context.Context, userId, imdbId string) error {
+	oid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return ErrRecordNotFound
+	}
+
+	result, err := w.collection.DeleteOne(ctx, bson.M{"user_id": oid, "imdb_id": imdbId})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (w *watchlistRepository) DeleteWatchlistByUserId(ctx context.Context, userId string) error {
+	oid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.collection.DeleteMany(ctx, bson.M{"user_id": oid})
+	return err
+}
+
+func (w *watchlistRepository) DeleteWatchlistByImdbId(ctx context.Context, imdbId string) error {
+	_, err := w.collection.DeleteMany(ctx, bson.M{"imdb_id": imdbId})
+	return err
+}
+
+// userKeysetPipeline reads one page of a user's documents ordered by field,
+// newest first, and joins in the movie each one references by imdb_id. The
+// join happens after the limit so only the page itself is looked up.
+func userKeysetPipeline(userId bson.ObjectID, field string, cursor *domain.Cursor, limit int64, movieCollection string) (mongo.Pipeline, error) {
+	match, sort := bson.M{"user_id": userId}, bson.D{{Key: field, Value: -1}, {Key: "_id", Value: 1}}
+	if cursor != nil {
+		key, err := time.Parse(time.RFC3339Nano, cursor.Key)
+		if err != nil {
+			return nil, fmt.Errorf("cursor key: %w", err)
+		}
+
+		after, err := keysetFilter(field, -1, key, cursor)
+		if err != nil {
+			return nil, err
+		}
+		match = bson.M{"$and": bson.A{match, after}}
+
+		if cursor.Backward {
+			sort = reverseSort(sort)
+		}
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: sort}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         movieCollection,
+			"localField":   "imdb_id",
+			"foreignField": "imdb_id",
+			"as":           "movie",
+		}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$movie", "preserveNullAndEmptyArrays": true}}},
+	}, nil
+}
+
+func NewWatchlistRepository(database *mongo.Database, collectionName, movieCollectionName string) WatchlistRepository {
+	return &watchlistRepository{
+		collection:      database.Collection(collectionName),
+		movieCollection: movieCollectionName,
+	}
+}
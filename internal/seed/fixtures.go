@@ -0,0 +1,73 @@
+package seed
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"os"
+	"path/filepath"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+// UserFixture describes a user to seed. Leaving Password empty gets a random
+// password, which is logged once when the user is created.
+type UserFixture struct {
+	FirstName      string      `json:"first_name"`
+	LastName       string      `json:"last_name"`
+	Email          string      `json:"email"`
+	Password       string      `json:"password"`
+	Role           string      `json:"role"`
+	FavoriteGenres []dto.Genre `json:"favorite_genres"`
+}
+
+type Fixtures struct {
+	Genres   []dto.Genre          `json:"genres"`
+	Rankings []dto.Ranking        `json:"rankings"`
+	Movies   []dto.CreateMovieReq `json:"movies"`
+	Users    []UserFixture        `json:"users"`
+}
+
+func (f *Fixtures) Merge(other *Fixtures) {
+	f.Genres = append(f.Genres, other.Genres...)
+	f.Rankings = append(f.Rankings, other.Rankings...)
+	f.Movies = append(f.Movies, other.Movies...)
+	f.Users = append(f.Users, other.Users...)
+}
+
+func LoadFile(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+	case ".yaml", ".yml":
+		data, err = yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported fixture format", path)
+	}
+
+	var fixtures Fixtures
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return &fixtures, nil
+}
+
+func LoadFiles(paths ...string) (*Fixtures, error) {
+	fixtures := &Fixtures{}
+	for _, path := range paths {
+		f, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		fixtures.Merge(f)
+	}
+	return fixtures, nil
+}
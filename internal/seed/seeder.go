@@ -0,0 +1,173 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+type Seeder struct {
+	genreRepository   repository.GenreRepository
+	rankingRepository repository.RankingRepository
+	movieRepository   repository.MovieRepository
+	userRepository    repository.UserRepository
+	roleRepository    repository.RoleRepository
+	passwordHasher    utils.PasswordHasher
+	logger            *slog.Logger
+}
+
+func (s *Seeder) Seed(ctx context.Context, fixtures *Fixtures) error {
+	for i := range fixtures.Genres {
+		genre := domain.Genre{
+			GenreId:   fixtures.Genres[i].GenreId,
+			GenreName: fixtures.Genres[i].GenreName,
+		}
+		if err := s.genreRepository.UpsertGenre(ctx, &genre); err != nil {
+			return fmt.Errorf("genre %d: %w", genre.GenreId, err)
+		}
+	}
+	s.logger.Info("genres seeded", "count", len(fixtures.Genres))
+
+	for i := range fixtures.Rankings {
+		ranking := domain.Ranking{
+			RankingValue: fixtures.Rankings[i].RankingValue,
+			RankingName:  fixtures.Rankings[i].RankingName,
+		}
+		if err := s.rankingRepository.UpsertRanking(ctx, &ranking); err != nil {
+			return fmt.Errorf("ranking %d: %w", ranking.RankingValue, err)
+		}
+	}
+	s.logger.Info("rankings seeded", "count", len(fixtures.Rankings))
+
+	inserted := 0
+	for i := range fixtures.Movies {
+		req := &fixtures.Movies[i]
+
+		v := helper.NewValidator()
+		dto.ValidateCreateMovieReq(v, req)
+		if !v.Valid() {
+			return fmt.Errorf("movie %q: %s", req.ImdbId, validationErrors(v))
+		}
+
+		movie := dto.FromCreateMovieReq(req)
+		movie.CreatedAt = time.Now()
+		movie.UpdatedAt = time.Now()
+		movie.Version = 1
+
+		created, err := s.movieRepository.CreateMovieIfNotExists(ctx, movie)
+		if err != nil {
+			return fmt.Errorf("movie %q: %w", req.ImdbId, err)
+		}
+		if created {
+			inserted++
+		}
+	}
+	s.logger.Info("movies seeded", "count", len(fixtures.Movies), "inserted", inserted)
+
+	inserted = 0
+	for i := range fixtures.Users {
+		fixture := &fixtures.Users[i]
+
+		generated := fixture.Password == ""
+		if generated {
+			password, err := utils.GenerateRandomToken(16)
+			if err != nil {
+				return fmt.Errorf("user %q: %w", fixture.Email, err)
+			}
+			fixture.Password = password
+		}
+
+		user, err := s.toUser(ctx, fixture)
+		if err != nil {
+			return fmt.Errorf("user %q: %w", fixture.Email, err)
+		}
+
+		created, err := s.userRepository.CreateUserIfNotExists(ctx, user)
+		if err != nil {
+			return fmt.Errorf("user %q: %w", user.Email, err)
+		}
+		if created {
+			inserted++
+			if generated {
+				s.logger.Warn("seeded user with a generated password, it is not shown again", "email", user.Email, "role", string(user.Role), "password", fixture.Password)
+			}
+		}
+	}
+	s.logger.Info("users seeded", "count", len(fixtures.Users), "inserted", inserted)
+
+	return nil
+}
+
+func (s *Seeder) toUser(ctx context.Context, fixture *UserFixture) (*domain.User, error) {
+	if fixture.Role == "" {
+		fixture.Role = string(domain.UserRoleUser)
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateRegister(v, &dto.RegisterReq{
+		FirstName: fixture.FirstName,
+		LastName:  fixture.LastName,
+		Email:     fixture.Email,
+		Password:  fixture.Password,
+	})
+	if !v.Valid() {
+		return nil, fmt.Errorf("%s", validationErrors(v))
+	}
+
+	if _, err := s.roleRepository.GetRole(ctx, fixture.Role); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, fmt.Errorf("unknown role %q", fixture.Role)
+		}
+		return nil, err
+	}
+
+	hashedPassword, err := s.passwordHasher.Hash(fixture.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &domain.User{
+		FirstName:      fixture.FirstName,
+		LastName:       fixture.LastName,
+		Email:          domain.NormalizeEmail(fixture.Email),
+		Password:       hashedPassword,
+		Role:           domain.UserRole(fixture.Role),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+		FavoriteGenres: dto.FromGenresReq(fixture.FavoriteGenres),
+		Version:        1,
+		EmailVerified:  true,
+		VerifiedAt:     &now,
+	}, nil
+}
+
+func validationErrors(v *helper.Validator) string {
+	parts := make([]string, 0, len(v.Errors))
+	for _, key := range slices.Sorted(maps.Keys(v.Errors)) {
+		parts = append(parts, key+" "+v.Errors[key])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func NewSeeder(genreRepository repository.GenreRepository, rankingRepository repository.RankingRepository, movieRepository repository.MovieRepository, userRepository repository.UserRepository, roleRepository repository.RoleRepository, passwordHasher utils.PasswordHasher, logger *slog.Logger) *Seeder {
+	return &Seeder{
+		genreRepository:   genreRepository,
+		rankingRepository: rankingRepository,
+		movieRepository:   movieRepository,
+		userRepository:    userRepository,
+		roleRepository:    roleRepository,
+		passwordHasher:    passwordHasher,
+		logger:            logger,
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/authz"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, userId string, input *dto.CreateAPIKeyReq) (*dto.CreatedAPIKeyResp, error)
+	GetAPIKeys(ctx context.Context, userId string) ([]dto.APIKeyResp, error)
+	DeleteAPIKey(ctx context.Context, userId, id string) error
+	Authenticate(ctx context.Context, key string) (*utils.Claims, error)
+}
+
+type apiKeyService struct {
+	config           *config.Config
+	apiKeyRepository repository.APIKeyRepository
+	userRepository   repository.UserRepository
+	permissionCache  *authz.PermissionCache
+	logger           *slog.Logger
+}
+
+func (a *apiKeyService) CreateAPIKey(ctx context.Context, userId string, input *dto.CreateAPIKeyReq) (*dto.CreatedAPIKeyResp, error) {
+	// A leaked key must not be able to outlive its own revocation by minting
+	// successors.
+	if _, ok := utils.APIKeyIdFromCtx(ctx); ok {
+		return nil, ErrAPIKeyNotAllowed
+	}
+
+	held, _ := utils.PermissionsFromCtx(ctx)
+	scopes := make([]domain.Permission, len(input.Scopes))
+	for i, s := range input.Scopes {
+		if !slices.Contains(held, s) {
+			return nil, ErrAPIKeyScopeNotHeld
+		}
+		scopes[i] = domain.Permission(s)
+	}
+
+	if _, err := a.userRepository.GetUserById(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	key, prefix, err := utils.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := &domain.APIKey{
+		UserId:    userId,
+		Name:      input.Name,
+		Prefix:    prefix,
+		KeyHash:   utils.HashToken(key),
+		Scopes:    scopes,
+		ExpiresAt: input.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	if err := a.apiKeyRepository.CreateAPIKey(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	a.logger.Info("api key created", "user_id", userId, "api_key_id", apiKey.Id, "prefix", prefix)
+
+	return &dto.CreatedAPIKeyResp{
+		APIKeyResp: *dto.ToAPIKeyResp(apiKey),
+		Key:        key,
+	}, nil
+}
+
+func (a *apiKeyService) GetAPIKeys(ctx context.Context, userId string) ([]dto.APIKeyResp, error) {
+	keys, err := a.apiKeyRepository.GetAPIKeysByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.APIKeyResp, len(keys))
+	for i := range keys {
+		response[i] = *dto.ToAPIKeyResp(&keys[i])
+	}
+
+	return response, nil
+}
+
+func (a *apiKeyService) DeleteAPIKey(ctx context.Context, userId, id string) error {
+	if _, ok := utils.APIKeyIdFromCtx(ctx); ok {
+		return ErrAPIKeyNotAllowed
+	}
+
+	if err := a.apiKeyRepository.DeleteAPIKey(ctx, userId, id); err != nil {
+		return err
+	}
+
+	a.logger.Info("api key deleted", "user_id", userId, "api_key_id", id)
+	return nil
+}
+
+// Authenticate resolves key to the claims an access token for its owner would
+// carry. The key's scopes are intersected with the owner's current role, so a
+// demoted user's keys lose the permissions the role no longer grants.
+func (a *apiKeyService) Authenticate(ctx context.Context, key string) (*utils.Claims, error) {
+	if !strings.HasPrefix(key, utils.APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := a.apiKeyRepository.GetAPIKeyByHash(ctx, utils.HashToken(key))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if apiKey.ExpiresAt != nil && !apiKey.ExpiresAt.After(now) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	user, err := a.userRepository.GetUserById(ctx, apiKey.UserId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if !user.EmailVerified && a.config.Auth.UnverifiedLogin == "deny" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	role, _ := effectiveRole(a.config, user)
+
+	permissions := []string{}
+	if role != domain.UserRoleRestricted {
+		rolePermissions, err := a.permissionCache.Permissions(ctx, role)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, scope := range apiKey.Scopes {
+			if slices.Contains(rolePermissions, scope) {
+				permissions = append(permissions, string(scope))
+			}
+		}
+	}
+
+	if err := a.apiKeyRepository.TouchAPIKey(ctx, apiKey.Id, now); err != nil {
+		a.logger.Warn("failed to record api key use", "api_key_id", apiKey.Id, "error", err.Error())
+	}
+
+	return &utils.Claims{
+		FirstName:   user.FirstName,
+		LastName:    user.LastName,
+		Email:       user.Email,
+		Role:        string(role),
+		UserId:      user.Id,
+		Permissions: permissions,
+		TokenType:   utils.TokenTypeAPIKey,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID: apiKey.Id,
+		},
+	}, nil
+}
+
+func NewAPIKeyService(config *config.Config, apiKeyRepository repository.APIKeyRepository, userRepository repository.UserRepository, permissionCache *authz.PermissionCache, logger *slog.Logger) APIKeyService {
+	return &apiKeyService{
+		config:           config,
+		apiKeyRepository: apiKeyRepository,
+		userRepository:   userRepository,
+		permissionCache:  permissionCache,
+		logger:           logger,
+	}
+}
@@ -5,27 +5,53 @@ import (
 	"errors"
 	"fmt"
 	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"github.com/saleh-ghazimoradi/Projectopher/infra/mailer"
+	"github.com/saleh-ghazimoradi/Projectopher/infra/oidc"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/authz"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
 	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"log/slog"
+	"sync"
 	"time"
 )
 
 type AuthService interface {
-	Register(ctx context.Context, input *dto.RegisterReq) (*dto.AuthResp, error)
-	Login(ctx context.Context, input *dto.LoginReq) (*dto.AuthResp, error)
-	RefreshToken(ctx context.Context, input *dto.RefreshTokenReq) (*dto.AuthResp, error)
+	Register(ctx context.Context, input *dto.RegisterReq, client dto.ClientInfo) (*dto.AuthResp, error)
+	Login(ctx context.Context, input *dto.LoginReq, client dto.ClientInfo) (*dto.AuthResp, error)
+	VerifyMFA(ctx context.Context, input *dto.MFAVerifyReq, client dto.ClientInfo) (*dto.AuthResp, error)
+	RefreshToken(ctx context.Context, input *dto.RefreshTokenReq, client dto.ClientInfo) (*dto.AuthResp, error)
 	Logout(ctx context.Context, input *dto.RefreshTokenReq) error
+	ForgotPassword(ctx context.Context, input *dto.ForgotPasswordReq) error
+	ResetPassword(ctx context.Context, input *dto.ResetPasswordReq) error
+	VerifyEmail(ctx context.Context, input *dto.VerifyEmailReq) error
+	ResendVerification(ctx context.Context, input *dto.ResendVerificationReq) error
+	OIDCAuthorize(ctx context.Context, provider string) (*dto.OIDCAuthorizeResp, error)
+	OIDCLogin(ctx context.Context, provider string, input *dto.OIDCCallbackReq, client dto.ClientInfo) (*dto.AuthResp, error)
 }
 
 type authService struct {
-	config          *config.Config
-	userRepository  repository.UserRepository
-	tokenRepository repository.TokenRepository
+	config                 *config.Config
+	keys                   *utils.KeySet
+	userRepository         repository.UserRepository
+	tokenRepository        repository.TokenRepository
+	loginAttemptRepository repository.LoginAttemptRepository
+	apiKeyRepository       repository.APIKeyRepository
+	oidcStateRepository    repository.OIDCStateRepository
+	oidcProviders          *oidc.Providers
+	permissionCache        *authz.PermissionCache
+	denylist               *authz.Denylist
+	mailer                 mailer.Mailer
+	passwordHasher         utils.PasswordHasher
+	logger                 *slog.Logger
+
+	dummyHashOnce sync.Once
+	dummyHash     string
 }
 
-func (a *authService) Register(ctx context.Context, input *dto.RegisterReq) (*dto.AuthResp, error) {
+func (a *authService) Register(ctx context.Context, input *dto.RegisterReq, client dto.ClientInfo) (*dto.AuthResp, error) {
+	input.Email = domain.NormalizeEmail(input.Email)
 	existing, err := a.userRepository.GetUserByEmail(ctx, input.Email)
 	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
 		return nil, fmt.Errorf("failed to get user by email: %w", err)
@@ -42,51 +68,395 @@ func (a *authService) Register(ctx context.Context, input *dto.RegisterReq) (*dt
 		return nil, err
 	}
 
-	return a.generateAuthResp(ctx, user)
+	// The account exists at this point, so failing the request would only make
+	// a retry hit the duplicate email check. The user can ask for a resend.
+	if err := a.sendVerificationEmail(ctx, user); err != nil {
+		a.logger.Error("failed to send verification email", "user_id", user.Id, "error", err.Error())
+	}
+
+	if a.config.Auth.UnverifiedLogin == "deny" {
+		return &dto.AuthResp{User: a.toUserResp(user)}, nil
+	}
+
+	return a.generateAuthResp(ctx, user, client, nil)
 }
 
-func (a *authService) Login(ctx context.Context, input *dto.LoginReq) (*dto.AuthResp, error) {
+func (a *authService) Login(ctx context.Context, input *dto.LoginReq, client dto.ClientInfo) (*dto.AuthResp, error) {
+	input.Email = domain.NormalizeEmail(input.Email)
+	accountKey, ipKey := accountAttemptKey(input.Email), ipAttemptKey(client.IP)
+	if err := a.checkLoginLock(ctx, accountKey, ipKey); err != nil {
+		return nil, err
+	}
+
 	user, err := a.userRepository.GetUserByEmail(ctx, input.Email)
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			// Verify anyway so an unknown email takes as long as a wrong password.
+			_, _ = a.passwordHasher.Verify(a.unknownUserHash(), input.Password)
+			return nil, a.loginFailed(ctx, accountKey, ipKey, ErrInvalidCredentials)
+		}
+		return nil, err
+	}
+
+	ok, err := a.passwordHasher.Verify(user.Password, input.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify password: %w", err)
+	}
+	if !ok {
+		return nil, a.loginFailed(ctx, accountKey, ipKey, ErrInvalidCredentials)
+	}
+
+	if err := a.loginAttemptRepository.DeleteLoginAttempt(ctx, accountKey); err != nil {
+		return nil, err
+	}
+
+	if a.passwordHasher.NeedsRehash(user.Password) {
+		hashedPassword, err := a.passwordHasher.Hash(input.Password)
+		if err != nil {
+			return nil, err
+		}
+
+		user.Password = hashedPassword
+		user.UpdatedAt = time.Now()
+		if err := a.userRepository.UpdatePassword(ctx, user); err != nil {
+			return nil, fmt.Errorf("failed to upgrade password hash: %w", err)
+		}
+	}
+
+	if !user.EmailVerified && a.config.Auth.UnverifiedLogin == "deny" {
+		return nil, ErrEmailNotVerified
 	}
 
-	if !utils.CheckPassword(user.Password, input.Password) {
-		return nil, errors.New("invalid credentials")
+	if user.MFA.Enabled {
+		return a.mfaChallenge(user)
 	}
 
-	return a.generateAuthResp(ctx, user)
+	return a.generateAuthResp(ctx, user, client, nil)
 }
 
-func (a *authService) RefreshToken(ctx context.Context, input *dto.RefreshTokenReq) (*dto.AuthResp, error) {
-	claim, err := utils.ValidateToken(input.RefreshToken, a.config.JWT.Secret)
+func (a *authService) mfaChallenge(user *domain.User) (*dto.AuthResp, error) {
+	mfaToken, err := utils.GenerateScopedToken(a.config, a.keys, utils.PurposeMFAChallenge, user.Id, user.Email, a.config.MFA.ChallengeExpires)
 	if err != nil {
-		return nil, errors.New("invalid refresh token")
+		return nil, fmt.Errorf("failed to generate mfa challenge: %w", err)
 	}
+	return &dto.AuthResp{MFARequired: true, MFAToken: mfaToken}, nil
+}
 
-	storedToken, err := a.tokenRepository.GetValidRefreshToken(ctx, input.RefreshToken)
+func (a *authService) VerifyMFA(ctx context.Context, input *dto.MFAVerifyReq, client dto.ClientInfo) (*dto.AuthResp, error) {
+	claims, err := utils.ValidateScopedToken(a.config, a.keys, utils.PurposeMFAChallenge, input.MFAToken)
 	if err != nil {
-		return nil, errors.New("refresh token not found or expired")
+		return nil, ErrInvalidToken
+	}
+
+	mfaKey, ipKey := mfaAttemptKey(claims.UserId), ipAttemptKey(client.IP)
+	if err := a.checkLoginLock(ctx, mfaKey, ipKey); err != nil {
+		return nil, err
 	}
 
-	user, err := a.userRepository.GetUserById(ctx, claim.UserId)
+	user, err := a.userRepository.GetUserById(ctx, claims.UserId)
 	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if !user.MFA.Enabled {
+		return nil, ErrInvalidToken
+	}
+
+	if err := verifyMFACode(ctx, a.userRepository, user, input.Code); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			return nil, a.loginFailed(ctx, mfaKey, ipKey, err)
+		}
 		return nil, err
 	}
 
-	if err := a.tokenRepository.DeleteRefreshTokenById(ctx, storedToken.Id); err != nil {
+	if err := a.loginAttemptRepository.DeleteLoginAttempt(ctx, mfaKey); err != nil {
 		return nil, err
 	}
 
-	return a.generateAuthResp(ctx, user)
+	return a.generateAuthResp(ctx, user, client, nil)
+}
+
+func (a *authService) RefreshToken(ctx context.Context, input *dto.RefreshTokenReq, client dto.ClientInfo) (*dto.AuthResp, error) {
+	// Refresh tokens are opaque, so a valid access token here is a client
+	// mixing the two up and must not reach the reuse check.
+	if _, err := utils.ValidateAccessToken(a.config, a.keys, input.RefreshToken); err == nil {
+		return nil, ErrInvalidToken
+	}
+
+	tokenHash := utils.HashToken(input.RefreshToken)
+	storedToken, err := a.tokenRepository.RotateRefreshToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, a.detectRefreshTokenReuse(ctx, tokenHash)
+		}
+		return nil, err
+	}
+
+	user, err := a.userRepository.GetUserById(ctx, storedToken.UserId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return a.generateAuthResp(ctx, user, client, storedToken)
+}
+
+// detectRefreshTokenReuse is called when a token could not be rotated. If it
+// exists but was already rotated, someone is replaying it: the whole family is
+// revoked so neither the attacker nor the legitimate client can keep using it.
+func (a *authService) detectRefreshTokenReuse(ctx context.Context, tokenHash string) error {
+	previous, err := a.tokenRepository.GetRefreshTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+
+	if previous.RotatedAt == nil {
+		return ErrInvalidToken
+	}
+
+	a.logger.Warn("refresh token reuse detected, revoking token family",
+		"user_id", previous.UserId,
+		"family_id", previous.FamilyId,
+		"rotated_at", previous.RotatedAt,
+	)
+
+	if err := revokeFamilyAccessTokens(ctx, a.tokenRepository, a.denylist, previous.FamilyId); err != nil {
+		return err
+	}
+
+	if err := a.tokenRepository.DeleteRefreshTokenFamily(ctx, previous.FamilyId); err != nil {
+		return err
+	}
+
+	return ErrRefreshTokenReuse
 }
 
 func (a *authService) Logout(ctx context.Context, input *dto.RefreshTokenReq) error {
-	return a.tokenRepository.DeleteRefreshToken(ctx, input.RefreshToken)
+	storedToken, err := a.tokenRepository.GetRefreshTokenByHash(ctx, utils.HashToken(input.RefreshToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := revokeFamilyAccessTokens(ctx, a.tokenRepository, a.denylist, storedToken.FamilyId); err != nil {
+		return err
+	}
+
+	return a.tokenRepository.DeleteRefreshTokenFamily(ctx, storedToken.FamilyId)
+}
+
+func (a *authService) ForgotPassword(ctx context.Context, input *dto.ForgotPasswordReq) error {
+	user, err := a.userRepository.GetUserByEmail(ctx, domain.NormalizeEmail(input.Email))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := a.tokenRepository.DeletePasswordResetTokensByUserId(ctx, user.Id); err != nil {
+		return err
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	resetToken := &domain.PasswordResetToken{
+		UserId:    user.Id,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(a.config.Auth.PasswordResetExpires),
+		CreatedAt: time.Now(),
+	}
+
+	if err := a.tokenRepository.CreatePasswordResetToken(ctx, resetToken); err != nil {
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	return a.mailer.Send(ctx, &mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to choose a new password. It expires in %s.\n\n%s?token=%s\n\nIf you did not request a password reset you can ignore this email.\n",
+			user.FirstName, a.config.Auth.PasswordResetExpires, a.config.Auth.PasswordResetURL, token),
+	})
+}
+
+func (a *authService) ResetPassword(ctx context.Context, input *dto.ResetPasswordReq) error {
+	resetToken, err := a.tokenRepository.ConsumePasswordResetToken(ctx, utils.HashToken(input.Token))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+
+	user, err := a.userRepository.GetUserById(ctx, resetToken.UserId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+
+	hashedPassword, err := a.passwordHasher.Hash(input.Password)
+	if err != nil {
+		return err
+	}
+
+	user.Password = hashedPassword
+	user.UpdatedAt = time.Now()
+
+	if err := a.userRepository.UpdatePassword(ctx, user); err != nil {
+		return err
+	}
+
+	if err := revokeUserAccessTokens(ctx, a.tokenRepository, a.denylist, user.Id); err != nil {
+		return err
+	}
+
+	if err := a.tokenRepository.DeleteRefreshTokensByUserId(ctx, user.Id); err != nil {
+		return err
+	}
+
+	return a.apiKeyRepository.DeleteAPIKeysByUserId(ctx, user.Id)
+}
+
+func (a *authService) VerifyEmail(ctx context.Context, input *dto.VerifyEmailReq) error {
+	claims, err := utils.ValidateScopedToken(a.config, a.keys, utils.PurposeEmailVerification, input.Token)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	user, err := a.userRepository.GetUserById(ctx, claims.UserId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+
+	if user.Email != claims.Email {
+		return ErrInvalidToken
+	}
+
+	if user.EmailVerified {
+		return nil
+	}
+
+	now := time.Now()
+	user.VerifiedAt = &now
+	user.UpdatedAt = now
+
+	if err := a.userRepository.MarkEmailVerified(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (a *authService) ResendVerification(ctx context.Context, input *dto.ResendVerificationReq) error {
+	user, err := a.userRepository.GetUserByEmail(ctx, domain.NormalizeEmail(input.Email))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if user.EmailVerified {
+		return nil
+	}
+
+	return a.sendVerificationEmail(ctx, user)
+}
+
+func (a *authService) sendVerificationEmail(ctx context.Context, user *domain.User) error {
+	token, err := utils.GenerateScopedToken(a.config, a.keys, utils.PurposeEmailVerification, user.Id, user.Email, a.config.Auth.EmailVerifyExpires)
+	if err != nil {
+		return fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	return a.mailer.Send(ctx, &mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address using the link below. It expires in %s.\n\n%s?token=%s\n",
+			user.FirstName, a.config.Auth.EmailVerifyExpires, a.config.Auth.EmailVerificationURL, token),
+	})
+}
+
+func (a *authService) checkLoginLock(ctx context.Context, keys ...string) error {
+	now := time.Now()
+	for _, key := range keys {
+		attempt, err := a.loginAttemptRepository.GetLoginAttempt(ctx, key)
+		if err != nil {
+			if errors.Is(err, repository.ErrRecordNotFound) {
+				continue
+			}
+			return err
+		}
+
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(now) {
+			return &LoginLockedError{RetryAfter: attempt.LockedUntil.Sub(now)}
+		}
+	}
+	return nil
+}
+
+// loginFailed records a failed attempt against the account and the client IP,
+// applying backoff or a lockout once the configured thresholds are reached.
+// It returns cause once the attempt has been recorded.
+func (a *authService) loginFailed(ctx context.Context, accountKey, ipKey string, cause error) error {
+	lockout := a.config.Lockout
+	now := time.Now()
+
+	account, err := a.loginAttemptRepository.RecordFailure(ctx, accountKey, now, now.Add(lockout.Window))
+	if err != nil {
+		return err
+	}
+
+	delay := loginBackoff(lockout, account.Failures)
+	if lockout.MaxAttempts > 0 && account.Failures >= lockout.MaxAttempts {
+		delay = lockout.Duration
+		a.logger.Warn("account locked after failed login attempts", "key", accountKey, "failures", account.Failures, "locked_for", delay.String())
+	}
+
+	if delay > 0 {
+		if err := a.loginAttemptRepository.Lock(ctx, accountKey, now.Add(delay)); err != nil {
+			return err
+		}
+	}
+
+	client, err := a.loginAttemptRepository.RecordFailure(ctx, ipKey, now, now.Add(lockout.Window))
+	if err != nil {
+		return err
+	}
+
+	if lockout.MaxAttemptsPerIP > 0 && client.Failures >= lockout.MaxAttemptsPerIP {
+		a.logger.Warn("ip locked after failed login attempts", "key", ipKey, "failures", client.Failures, "locked_for", lockout.Duration.String())
+		if err := a.loginAttemptRepository.Lock(ctx, ipKey, now.Add(lockout.Duration)); err != nil {
+			return err
+		}
+	}
+
+	return cause
 }
 
 func (a *authService) toUser(input *dto.RegisterReq) (*domain.User, error) {
-	hashedPassword, err := utils.HashPassword(input.Password)
+	hashedPassword, err := a.passwordHasher.Hash(input.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -108,26 +478,81 @@ func (a *authService) toUser(input *dto.RegisterReq) (*domain.User, error) {
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 		FavoriteGenres: favoriteGenres,
+		Version:        1,
 	}, nil
 }
 
-func (a *authService) generateAuthResp(ctx context.Context, user *domain.User) (*dto.AuthResp, error) {
-	accessToken, refreshToken, err := utils.GenerateToken(a.config, user.FirstName, user.LastName, user.Email, string(user.Role), user.Id)
+// effectiveRole is the role credentials issued to user carry: the restricted
+// role while the email is unverified under the restricted policy or while an
+// admin still has to enroll in MFA.
+func effectiveRole(cfg *config.Config, user *domain.User) (domain.UserRole, bool) {
+	enrollmentRequired := cfg.MFA.EnforceAdmin && user.Role == domain.UserRoleAdmin && !user.MFA.Enabled
+	if enrollmentRequired || (!user.EmailVerified && cfg.Auth.UnverifiedLogin == "restricted") {
+		return domain.UserRoleRestricted, enrollmentRequired
+	}
+	return user.Role, false
+}
+
+// generateAuthResp issues a new token pair. When previous is set the new
+// refresh token continues its family (session); otherwise a new one starts.
+func (a *authService) generateAuthResp(ctx context.Context, user *domain.User, client dto.ClientInfo, previous *domain.RefreshToken) (*dto.AuthResp, error) {
+	role, enrollmentRequired := effectiveRole(a.config, user)
+
+	var permissions []string
+	if role != domain.UserRoleRestricted {
+		rolePermissions, err := a.permissionCache.Permissions(ctx, role)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load permissions: %w", err)
+		}
+
+		permissions = make([]string, len(rolePermissions))
+		for i, p := range rolePermissions {
+			permissions[i] = string(p)
+		}
+	}
+
+	tokens, err := utils.GenerateToken(a.config, a.keys, user.FirstName, user.LastName, user.Email, string(role), user.Id, permissions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
 
+	now := time.Now()
 	refToken := &domain.RefreshToken{
-		UserId:    user.Id,
-		Token:     refreshToken,
-		ExpiresAt: time.Now().Add(a.config.JWT.RefreshTokenExpires),
-		CreatedAt: time.Now(),
+		UserId:           user.Id,
+		TokenHash:        utils.HashToken(tokens.RefreshToken),
+		AccessTokenId:    tokens.AccessTokenId,
+		AccessExpiresAt:  tokens.AccessExpiresAt,
+		UserAgent:        client.UserAgent,
+		IP:               client.IP,
+		SessionCreatedAt: now,
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(a.config.JWT.RefreshTokenExpires),
+		CreatedAt:        now,
+	}
+
+	if previous != nil {
+		refToken.FamilyId = previous.FamilyId
+		refToken.SessionCreatedAt = previous.SessionCreatedAt
+	} else {
+		refToken.FamilyId, err = utils.GenerateRandomToken(16)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if err := a.tokenRepository.CreateRefreshToken(ctx, refToken); err != nil {
 		return nil, fmt.Errorf("failed to create refresh token: %w", err)
 	}
 
+	return &dto.AuthResp{
+		User:                  a.toUserResp(user),
+		AccessToken:           tokens.AccessToken,
+		RefreshToken:          tokens.RefreshToken,
+		MFAEnrollmentRequired: enrollmentRequired,
+	}, nil
+}
+
+func (a *authService) toUserResp(user *domain.User) *dto.UserResp {
 	genres := make([]dto.Genre, len(user.FavoriteGenres))
 	for i := range genres {
 		genres[i] = dto.Genre{
@@ -136,26 +561,42 @@ func (a *authService) generateAuthResp(ctx context.Context, user *domain.User) (
 		}
 	}
 
-	return &dto.AuthResp{
-		User: dto.UserResp{
-			Id:             user.Id,
-			FirstName:      user.FirstName,
-			LastName:       user.LastName,
-			Email:          user.Email,
-			Role:           string(user.Role),
-			CreatedAt:      user.CreatedAt,
-			UpdatedAt:      user.UpdatedAt,
-			FavoriteGenres: genres,
-		},
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, nil
+	return &dto.UserResp{
+		Id:             user.Id,
+		FirstName:      user.FirstName,
+		LastName:       user.LastName,
+		Email:          user.Email,
+		Role:           string(user.Role),
+		CreatedAt:      user.CreatedAt,
+		UpdatedAt:      user.UpdatedAt,
+		FavoriteGenres: genres,
+		Version:        user.Version,
+		EmailVerified:  user.EmailVerified,
+		MFAEnabled:     user.MFA.Enabled,
+	}
+}
+
+func (a *authService) unknownUserHash() string {
+	a.dummyHashOnce.Do(func() {
+		a.dummyHash, _ = a.passwordHasher.Hash("projectopher-unknown-user")
+	})
+	return a.dummyHash
 }
 
-func NewAuthService(config *config.Config, userRepository repository.UserRepository, tokenRepository repository.TokenRepository) AuthService {
+func NewAuthService(config *config.Config, keys *utils.KeySet, userRepository repository.UserRepository, tokenRepository repository.TokenRepository, loginAttemptRepository repository.LoginAttemptRepository, apiKeyRepository repository.APIKeyRepository, oidcStateRepository repository.OIDCStateRepository, oidcProviders *oidc.Providers, permissionCache *authz.PermissionCache, denylist *authz.Denylist, mailer mailer.Mailer, passwordHasher utils.PasswordHasher, logger *slog.Logger) AuthService {
 	return &authService{
-		config:          config,
-		userRepository:  userRepository,
-		tokenRepository: tokenRepository,
+		config:                 config,
+		keys:                   keys,
+		userRepository:         userRepository,
+		tokenRepository:        tokenRepository,
+		loginAttemptRepository: loginAttemptRepository,
+		apiKeyRepository:       apiKeyRepository,
+		oidcStateRepository:    oidcStateRepository,
+		oidcProviders:          oidcProviders,
+		permissionCache:        permissionCache,
+		denylist:               denylist,
+		mailer:                 mailer,
+		passwordHasher:         passwordHasher,
+		logger:                 logger,
 	}
 }
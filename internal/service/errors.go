@@ -0,0 +1,34 @@
+package service
+
+import "errors"
+
+var (
+	ErrGenreInUse            = errors.New("genre is still referenced by movies or users")
+	ErrRankingInUse          = errors.New("ranking is still referenced by movies")
+	ErrInvalidReassignTarget = errors.New("invalid reassign target")
+	ErrUnknownRole           = errors.New("unknown role")
+	ErrUnknownRanking        = errors.New("ranking does not match a known ranking")
+	ErrInvalidToken          = errors.New("invalid or expired token")
+	ErrInvalidCredentials    = errors.New("invalid credentials")
+	ErrEmailNotVerified      = errors.New("email address is not verified")
+	ErrIncorrectPassword     = errors.New("current password is incorrect")
+	ErrLoginLocked           = errors.New("too many failed login attempts")
+	ErrRefreshTokenReuse     = errors.New("refresh token reuse detected")
+	ErrInvalidMFACode        = errors.New("invalid mfa code")
+	ErrMFAAlreadyEnabled     = errors.New("mfa is already enabled")
+	ErrMFANotEnrolled        = errors.New("mfa enrollment has not been started")
+	ErrMFANotEnabled         = errors.New("mfa is not enabled")
+	ErrInvalidAPIKey         = errors.New("invalid or expired api key")
+	ErrAPIKeyScopeNotHeld    = errors.New("api key scopes must be permissions the caller holds")
+	ErrAPIKeyNotAllowed      = errors.New("api keys cannot be managed with an api key")
+	ErrUnknownOIDCProvider   = errors.New("unknown login provider")
+	ErrOIDCLoginFailed       = errors.New("login with provider failed")
+	ErrOIDCEmailNotVerified  = errors.New("provider did not return a verified email address")
+	ErrOIDCAccountConflict   = errors.New("email address belongs to an account that cannot be linked")
+	ErrInvalidCursor         = errors.New("invalid cursor")
+	ErrUnknownMovie          = errors.New("movie does not exist")
+	ErrMovieAlreadyListed    = errors.New("movie is already on the list")
+	ErrMovieNotListed        = errors.New("movie is not on the list")
+	ErrMovieListFull         = errors.New("list already holds the maximum number of movies")
+	ErrInvalidListOrder      = errors.New("order must name every movie on the list exactly once")
+)
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+)
+
+type GenreService interface {
+	CreateGenre(ctx context.Context, input *dto.CreateGenreReq) (*dto.Genre, error)
+	GetGenre(ctx context.Context, genreId int) (*dto.Genre, error)
+	UpdateGenre(ctx context.Context, genreId int, input *dto.UpdateGenreReq) (*dto.Genre, error)
+	DeleteGenre(ctx context.Context, genreId int, reassignTo *int) error
+}
+
+type genreService struct {
+	genreRepository repository.GenreRepository
+	movieRepository repository.MovieRepository
+	userRepository  repository.UserRepository
+}
+
+func (g *genreService) CreateGenre(ctx context.Context, input *dto.CreateGenreReq) (*dto.Genre, error) {
+	genre := &domain.Genre{
+		GenreId:   input.GenreId,
+		GenreName: input.GenreName,
+	}
+
+	if err := g.genreRepository.CreateGenre(ctx, genre); err != nil {
+		return nil, err
+	}
+
+	return dto.ToGenreResp(genre), nil
+}
+
+func (g *genreService) GetGenre(ctx context.Context, genreId int) (*dto.Genre, error) {
+	genre, err := g.genreRepository.GetGenre(ctx, genreId)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToGenreResp(genre), nil
+}
+
+func (g *genreService) UpdateGenre(ctx context.Context, genreId int, input *dto.UpdateGenreReq) (*dto.Genre, error) {
+	genre, err := g.genreRepository.GetGenre(ctx, genreId)
+	if err != nil {
+		return nil, err
+	}
+
+	genre.GenreName = *input.GenreName
+
+	if err := g.genreRepository.UpdateGenre(ctx, genre); err != nil {
+		return nil, err
+	}
+
+	if err := g.movieRepository.RenameGenre(ctx, genre); err != nil {
+		return nil, err
+	}
+
+	if err := g.userRepository.RenameFavoriteGenre(ctx, genre); err != nil {
+		return nil, err
+	}
+
+	return dto.ToGenreResp(genre), nil
+}
+
+func (g *genreService) DeleteGenre(ctx context.Context, genreId int, reassignTo *int) error {
+	if _, err := g.genreRepository.GetGenre(ctx, genreId); err != nil {
+		return err
+	}
+
+	if reassignTo == nil {
+		movies, err := g.movieRepository.CountMoviesByGenre(ctx, genreId)
+		if err != nil {
+			return err
+		}
+
+		users, err := g.userRepository.CountUsersByFavoriteGenre(ctx, genreId)
+		if err != nil {
+			return err
+		}
+
+		if movies > 0 || users > 0 {
+			return ErrGenreInUse
+		}
+
+		return g.genreRepository.DeleteGenre(ctx, genreId)
+	}
+
+	if *reassignTo == genreId {
+		return ErrInvalidReassignTarget
+	}
+
+	target, err := g.genreRepository.GetGenre(ctx, *reassignTo)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrInvalidReassignTarget
+		}
+		return err
+	}
+
+	if err := g.movieRepository.ReassignGenre(ctx, genreId, target); err != nil {
+		return err
+	}
+
+	if err := g.userRepository.ReassignFavoriteGenre(ctx, genreId, target); err != nil {
+		return err
+	}
+
+	return g.genreRepository.DeleteGenre(ctx, genreId)
+}
+
+func NewGenreService(genreRepository repository.GenreRepository, movieRepository repository.MovieRepository, userRepository repository.UserRepository) GenreService {
+	return &genreService{
+		genreRepository: genreRepository,
+		movieRepository: movieRepository,
+		userRepository:  userRepository,
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"fmt"
+	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"strings"
+	"time"
+)
+
+// LoginLockedError is returned by Login while an account or IP is backing off
+// or locked out. It unwraps to ErrLoginLocked.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrLoginLocked, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginLockedError) Unwrap() error {
+	return ErrLoginLocked
+}
+
+func accountAttemptKey(email string) string {
+	return "account:" + strings.ToLower(email)
+}
+
+func mfaAttemptKey(userId string) string {
+	return "mfa:" + userId
+}
+
+func ipAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+// loginBackoff lets the first failure through and doubles the delay for each
+// failure after that, capped at BackoffMax.
+func loginBackoff(cfg config.Lockout, failures int) time.Duration {
+	if failures < 2 || cfg.BackoffBase <= 0 {
+		return 0
+	}
+
+	delay := cfg.BackoffBase
+	for i := 2; i < failures && delay < cfg.BackoffMax; i++ {
+		delay *= 2
+	}
+
+	return min(delay, cfg.BackoffMax)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"testing"
+	"time"
+)
+
+func TestLoginBackoff(t *testing.T) {
+	lockout := config.Lockout{BackoffBase: time.Second, BackoffMax: time.Minute}
+
+	tests := []struct {
+		name     string
+		cfg      config.Lockout
+		failures int
+		want     time.Duration
+	}{
+		{name: "no failures", cfg: lockout, failures: 0, want: 0},
+		{name: "first failure", cfg: lockout, failures: 1, want: 0},
+		{name: "second failure", cfg: lockout, failures: 2, want: time.Second},
+		{name: "third failure", cfg: lockout, failures: 3, want: 2 * time.Second},
+		{name: "fourth failure", cfg: lockout, failures: 4, want: 4 * time.Second},
+		{name: "seventh failure", cfg: lockout, failures: 7, want: 32 * time.Second},
+		{name: "capped", cfg: lockout, failures: 8, want: time.Minute},
+		{name: "stays capped", cfg: lockout, failures: 1000, want: time.Minute},
+		{name: "base above max", cfg: config.Lockout{BackoffBase: 2 * time.Minute, BackoffMax: time.Minute}, failures: 2, want: time.Minute},
+		{name: "disabled", cfg: config.Lockout{BackoffMax: time.Minute}, failures: 5, want: 0},
+		{name: "negative base", cfg: config.Lockout{BackoffBase: -time.Second, BackoffMax: time.Minute}, failures: 5, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := loginBackoff(tt.cfg, tt.failures); got != tt.want {
+				t.Fatalf("loginBackoff(%d) = %s, want %s", tt.failures, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginLockedError(t *testing.T) {
+	err := error(&LoginLockedError{RetryAfter: 1500 * time.Millisecond})
+
+	if !errors.Is(err, ErrLoginLocked) {
+		t.Fatalf("errors.Is(%v, ErrLoginLocked) = false", err)
+	}
+
+	var locked *LoginLockedError
+	if !errors.As(err, &locked) || locked.RetryAfter != 1500*time.Millisecond {
+		t.Fatalf("errors.As did not recover the retry delay from %v", err)
+	}
+}
+
+func TestAccountAttemptKeyIgnoresCase(t *testing.T) {
+	if accountAttemptKey("Jane@Example.com") != accountAttemptKey("jane@example.com") {
+		t.Fatal("account attempt keys differ by email case")
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"time"
+)
+
+// totpSkew accepts codes from one step either side of the current one.
+const totpSkew = 1
+
+type MFAService interface {
+	Enroll(ctx context.Context, id string) (*dto.MFAEnrollResp, error)
+	Confirm(ctx context.Context, id string, input *dto.MFACodeReq) (*dto.MFARecoveryCodesResp, error)
+	Disable(ctx context.Context, id string, input *dto.MFACodeReq) error
+}
+
+type mfaService struct {
+	config         *config.Config
+	userRepository repository.UserRepository
+}
+
+func (m *mfaService) Enroll(ctx context.Context, id string) (*dto.MFAEnrollResp, error) {
+	user, err := m.userRepository.GetUserById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.MFA.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	user.MFA.PendingSecret = secret
+	user.UpdatedAt = time.Now()
+
+	if err := m.userRepository.UpdateMFA(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &dto.MFAEnrollResp{
+		Secret:     secret,
+		OtpauthURI: utils.TOTPURI(m.config.MFA.Issuer, user.Email, secret),
+	}, nil
+}
+
+func (m *mfaService) Confirm(ctx context.Context, id string, input *dto.MFACodeReq) (*dto.MFARecoveryCodesResp, error) {
+	user, err := m.userRepository.GetUserById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.MFA.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	if user.MFA.PendingSecret == "" {
+		return nil, ErrMFANotEnrolled
+	}
+
+	step, ok := utils.ValidateTOTP(user.MFA.PendingSecret, input.Code, time.Now(), totpSkew)
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, err := utils.GenerateRecoveryCodes(m.config.MFA.RecoveryCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = utils.HashRecoveryCode(code)
+	}
+
+	user.MFA = domain.UserMFA{
+		Enabled:       true,
+		Secret:        user.MFA.PendingSecret,
+		RecoveryCodes: hashes,
+		LastStep:      step,
+	}
+	user.UpdatedAt = time.Now()
+
+	if err := m.userRepository.UpdateMFA(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &dto.MFARecoveryCodesResp{RecoveryCodes: codes}, nil
+}
+
+func (m *mfaService) Disable(ctx context.Context, id string, input *dto.MFACodeReq) error {
+	user, err := m.userRepository.GetUserById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !user.MFA.Enabled {
+		return ErrMFANotEnabled
+	}
+
+	if err := verifyMFACode(ctx, m.userRepository, user, input.Code); err != nil {
+		return err
+	}
+
+	user.MFA = domain.UserMFA{}
+	user.UpdatedAt = time.Now()
+
+	return m.userRepository.UpdateMFA(ctx, user)
+}
+
+// verifyMFACode accepts either a current TOTP code or an unused recovery code,
+// consuming it so neither can be replayed.
+func verifyMFACode(ctx context.Context, userRepository repository.UserRepository, user *domain.User, code string) error {
+	var err error
+	if step, ok := utils.ValidateTOTP(user.MFA.Secret, code, time.Now(), totpSkew); ok {
+		err = userRepository.ConsumeMFAStep(ctx, user.Id, step)
+	} else {
+		err = userRepository.ConsumeRecoveryCode(ctx, user.Id, utils.HashRecoveryCode(code))
+	}
+
+	if errors.Is(err, repository.ErrRecordNotFound) {
+		return ErrInvalidMFACode
+	}
+	return err
+}
+
+func NewMFAService(config *config.Config, userRepository repository.UserRepository) MFAService {
+	return &mfaService{
+		config:         config,
+		userRepository: userRepository,
+	}
+}
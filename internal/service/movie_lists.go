@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+type MovieListService interface {
+	CreateMovieList(ctx context.Context, ownerId string, input *dto.CreateMovieListReq) (*dto.MovieListResp, error)
+	GetMovieList(ctx context.Context, ownerId, id string) (*dto.MovieListResp, error)
+	GetSharedMovieList(ctx context.Context, slug string) (*dto.MovieListResp, error)
+	GetUserMovieLists(ctx context.Context, ownerId string, publicOnly bool) ([]dto.MovieListResp, error)
+	GetPublicMovieLists(ctx context.Context, input *dto.ListMovieListsReq) ([]dto.MovieListResp, *helper.PaginatedMeta, error)
+	GetPublicMovieListsByCursor(ctx context.Context, input *dto.ListMovieListsReq) ([]dto.MovieListResp, *helper.CursorMeta, error)
+	UpdateMovieList(ctx context.Context, ownerId, id string, input *dto.UpdateMovieListReq) (*dto.MovieListResp, error)
+	DeleteMovieList(ctx context.Context, ownerId, id string) error
+	AddMovieListItem(ctx context.Context, ownerId, id string, input *dto.AddMovieListItemReq) (*dto.MovieListResp, error)
+	RemoveMovieListItem(ctx context.Context, ownerId, id, imdbId string) (*dto.MovieListResp, error)
+	ReorderMovieList(ctx context.Context, ownerId, id string, input *dto.ReorderMovieListReq) (*dto.MovieListResp, error)
+}
+
+type movieListService struct {
+	movieListRepository repository.MovieListRepository
+	movieRepository     repository.MovieRepository
+	cursors             *utils.CursorSigner
+	logger              *slog.Logger
+}
+
+func (m *movieListService) CreateMovieList(ctx context.Context, ownerId string, input *dto.CreateMovieListReq) (*dto.MovieListResp, error) {
+	slug, err := utils.GenerateRandomToken(9)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	list := &domain.MovieList{
+		OwnerId:     ownerId,
+		Title:       input.Title,
+		Description: input.Description,
+		Visibility:  domain.ListVisibility(input.Visibility),
+		Slug:        slug,
+		Items:       make([]domain.MovieListItem, len(input.Items)),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Version:     1,
+	}
+
+	imdbIds := make([]string, len(input.Items))
+	for i, item := range input.Items {
+		list.Items[i] = domain.MovieListItem{ImdbId: item.ImdbId, Note: item.Note, AddedAt: now}
+		imdbIds[i] = item.ImdbId
+	}
+
+	movies, err := m.movies(ctx, imdbIds)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(movies) != len(imdbIds) {
+		return nil, ErrUnknownMovie
+	}
+
+	if err := m.movieListRepository.CreateMovieList(ctx, list); err != nil {
+		return nil, err
+	}
+
+	m.logger.Info("movie list created", "user_id", ownerId, "list_id", list.Id)
+	return dto.ToMovieListResp(list, movies), nil
+}
+
+func (m *movieListService) GetMovieList(ctx context.Context, ownerId, id string) (*dto.MovieListResp, error) {
+	list, err := m.movieListRepository.GetMovieList(ctx, ownerId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.toResp(ctx, list)
+}
+
+// GetSharedMovieList resolves a share link. Private lists are reported as
+// missing so a leaked slug reveals nothing once the owner hides the list.
+func (m *movieListService) GetSharedMovieList(ctx context.Context, slug string) (*dto.MovieListResp, error) {
+	list, err := m.movieListRepository.GetMovieListBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if list.Visibility == domain.ListVisibilityPrivate {
+		return nil, repository.ErrRecordNotFound
+	}
+
+	return m.toResp(ctx, list)
+}
+
+func (m *movieListService) GetUserMovieLists(ctx context.Context, ownerId string, publicOnly bool) ([]dto.MovieListResp, error) {
+	lists, err := m.movieListRepository.GetMovieListsByOwner(ctx, ownerId, publicOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.toListResp(ctx, lists)
+}
+
+func (m *movieListService) GetPublicMovieLists(ctx context.Context, input *dto.ListMovieListsReq) ([]dto.MovieListResp, *helper.PaginatedMeta, error) {
+	page, limit := input.Page, input.Limit
+	if page < 1 {
+		page = 1
+	}
+
+	if limit < 1 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+	total, err := m.movieListRepository.CountPublicMovieLists(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lists, err := m.movieListRepository.GetPublicMovieLists(ctx, offset, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response, err := m.toListResp(ctx, lists)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totalPages := (total + limit - 1) / limit
+	meta := &helper.PaginatedMeta{
+		Page:      page,
+		Limit:     limit,
+		Total:     total,
+		TotalPage: totalPages,
+	}
+
+	return response, meta, nil
+}
+
+func (m *movieListService) GetPublicMovieListsByCursor(ctx context.Context, input *dto.ListMovieListsReq) ([]dto.MovieListResp, *helper.CursorMeta, error) {
+	limit := input.Limit
+	if limit < 1 {
+		limit = 10
+	}
+
+	cursor, err := decodeCursor(m.cursors, *input.Cursor, domain.MovieListSortNewest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lists, err := m.movieListRepository.GetPublicMovieListsByCursor(ctx, cursor, limit+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lists, meta, err := cursorPage(m.cursors, domain.MovieListSortNewest, cursor, lists, limit, func(list *domain.MovieList) (string, string) {
+		return list.Id, list.SortKey()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if input.IncludeTotal {
+		total, err := m.movieListRepository.CountPublicMovieLists(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		meta.Total = &total
+	}
+
+	response, err := m.toListResp(ctx, lists)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, meta, nil
+}
+
+func (m *movieListService) UpdateMovieList(ctx context.Context, ownerId, id string, input *dto.UpdateMovieListReq) (*dto.MovieListResp, error) {
+	list, err := m.movieListRepository.GetMovieList(ctx, ownerId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkVersion(input.Version, list.Version); err != nil {
+		return nil, err
+	}
+
+	dto.ApplyUpdateMovieListReq(list, input)
+	return m.save(ctx, list)
+}
+
+func (m *movieListService) DeleteMovieList(ctx context.Context, ownerId, id string) error {
+	return m.movieListRepository.DeleteMovieList(ctx, ownerId, id)
+}
+
+func (m *movieListService) AddMovieListItem(ctx context.Context, ownerId, id string, input *dto.AddMovieListItemReq) (*dto.MovieListResp, error) {
+	list, err := m.movieListRepository.GetMovieList(ctx, ownerId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if list.IndexOf(input.ImdbId) >= 0 {
+		return nil, ErrMovieAlreadyListed
+	}
+
+	if len(list.Items) >= domain.MovieListMaxItems {
+		return nil, ErrMovieListFull
+	}
+
+	if _, err := m.movieRepository.GetMovie(ctx, input.ImdbId); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, ErrUnknownMovie
+		}
+		return nil, err
+	}
+
+	position := len(list.Items)
+	if input.Position != nil {
+		position = min(*input.Position-1, position)
+	}
+
+	item := domain.MovieListItem{ImdbId: input.ImdbId, Note: input.Note, AddedAt: time.Now()}
+	list.Items = slices.Insert(list.Items, position, item)
+	return m.save(ctx, list)
+}
+
+func (m *movieListService) RemoveMovieListItem(ctx context.Context, ownerId, id, imdbId string) (*dto.MovieListResp, error) {
+	list, err := m.movieListRepository.GetMovieList(ctx, ownerId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	i := list.IndexOf(imdbId)
+	if i < 0 {
+		return nil, ErrMovieNotListed
+	}
+
+	list.Items = slices.Delete(list.Items, i, i+1)
+	return m.save(ctx, list)
+}
+
+// ReorderMovieList puts the items in the order of input.ImdbIds, which must
+// name every movie on the list exactly once.
+func (m *movieListService) ReorderMovieList(ctx context.Context, ownerId, id string, input *dto.ReorderMovieListReq) (*dto.MovieListResp, error) {
+	list, err := m.movieListRepository.GetMovieList(ctx, ownerId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkVersion(input.Version, list.Version); err != nil {
+		return nil, err
+	}
+
+	if len(input.ImdbIds) != len(list.Items) {
+		return nil, ErrInvalidListOrder
+	}
+
+	items := make([]domain.MovieListItem, len(input.ImdbIds))
+	for i, imdbId := range input.ImdbIds {
+		j := list.IndexOf(imdbId)
+		if j < 0 {
+			return nil, ErrInvalidListOrder
+		}
+		items[i] = list.Items[j]
+	}
+
+	list.Items = items
+	return m.save(ctx, list)
+}
+
+func (m *movieListService) save(ctx context.Context, list *domain.MovieList) (*dto.MovieListResp, error) {
+	list.UpdatedAt = time.Now()
+	if err := m.movieListRepository.UpdateMovieList(ctx, list); err != nil {
+		return nil, err
+	}
+
+	return m.toResp(ctx, list)
+}
+
+func (m *movieListService) toResp(ctx context.Context, list *domain.MovieList) (*dto.MovieListResp, error) {
+	response, err := m.toListResp(ctx, []domain.MovieList{*list})
+	if err != nil {
+		return nil, err
+	}
+
+	return &response[0], nil
+}
+
+// toListResp fetches the movies of every list in one query.
+func (m *movieListService) toListResp(ctx context.Context, lists []domain.MovieList) ([]dto.MovieListResp, error) {
+	var imdbIds []string
+	for _, list := range lists {
+		for _, item := range list.Items {
+			imdbIds = append(imdbIds, item.ImdbId)
+		}
+	}
+
+	movies, err := m.movies(ctx, imdbIds)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.MovieListResp, len(lists))
+	for i := range lists {
+		response[i] = *dto.ToMovieListResp(&lists[i], movies)
+	}
+
+	return response, nil
+}
+
+func (m *movieListService) movies(ctx context.Context, imdbIds []string) (map[string]*domain.Movie, error) {
+	slices.Sort(imdbIds)
+	movies, err := m.movieRepository.GetMoviesByImdbIds(ctx, slices.Compact(imdbIds))
+	if err != nil {
+		return nil, err
+	}
+
+	byImdbId := make(map[string]*domain.Movie, len(movies))
+	for i := range movies {
+		byImdbId[movies[i].ImdbId] = &movies[i]
+	}
+
+	return byImdbId, nil
+}
+
+func NewMovieListService(movieListRepository repository.MovieListRepository, movieRepository repository.MovieRepository, cursors *utils.CursorSigner, logger *slog.Logger) MovieListService {
+	return &movieListService{
+		movieListRepository: movieListRepository,
+		movieRepository:     movieRepository,
+		cursors:             cursors,
+		logger:              logger,
+	}
+}
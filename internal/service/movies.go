@@ -9,28 +9,46 @@ import (
 	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"time"
 )
 
 type MovieService interface {
 	CreateMovie(ctx context.Context, input *dto.CreateMovieReq) (*dto.MovieResp, error)
 	GetMovie(ctx context.Context, id string) (*dto.MovieResp, error)
-	GetMovies(ctx context.Context, page, limit int64) ([]dto.MovieResp, *helper.PaginatedMeta, error)
+	GetMovies(ctx context.Context, input *dto.ListMoviesReq) ([]dto.MovieResp, *helper.PaginatedMeta, error)
+	GetMoviesByCursor(ctx context.Context, input *dto.ListMoviesReq) ([]dto.MovieResp, *helper.CursorMeta, error)
+	UpdateMovie(ctx context.Context, imdbId string, input *dto.UpdateMovieReq) (*dto.MovieResp, error)
+	ReplaceMovie(ctx context.Context, imdbId string, input *dto.ReplaceMovieReq) (*dto.MovieResp, error)
+	DeleteMovie(ctx context.Context, imdbId string) error
 	UpdateAdminReview(ctx context.Context, imdbId string, input *dto.AdminReviewUpdateReq) (*dto.AdminReviewResp, error)
 	GetRecommendedMovies(ctx context.Context, userId string) ([]dto.MovieResp, error)
 	GetGenres(ctx context.Context) ([]dto.Genre, error)
 }
 
 type movieService struct {
-	movieRepository   repository.MovieRepository
-	rankingRepository repository.RankingRepository
-	genreRepository   repository.GenreRepository
-	userRepository    repository.UserRepository
-	openAI            AI.OpenAI
-	config            *config.Config
+	movieRepository        repository.MovieRepository
+	rankingRepository      repository.RankingRepository
+	genreRepository        repository.GenreRepository
+	userRepository         repository.UserRepository
+	reviewRepository       repository.ReviewRepository
+	watchlistRepository    repository.WatchlistRepository
+	watchHistoryRepository repository.WatchHistoryRepository
+	movieListRepository    repository.MovieListRepository
+	openAI                 AI.OpenAI
+	cursors                *utils.CursorSigner
+	config                 *config.Config
 }
 
 func (m *movieService) CreateMovie(ctx context.Context, input *dto.CreateMovieReq) (*dto.MovieResp, error) {
 	movie := dto.FromCreateMovieReq(input)
+	if err := m.checkRanking(ctx, &movie.Ranking); err != nil {
+		return nil, err
+	}
+
+	movie.CreatedAt = time.Now()
+	movie.UpdatedAt = time.Now()
+	movie.Version = 1
 	if err := m.movieRepository.CreateMovie(ctx, movie); err != nil {
 		return nil, err
 	}
@@ -45,7 +63,8 @@ func (m *movieService) GetMovie(ctx context.Context, id string) (*dto.MovieResp,
 	return dto.ToMovieResp(movie), nil
 }
 
-func (m *movieService) GetMovies(ctx context.Context, page, limit int64) ([]dto.MovieResp, *helper.PaginatedMeta, error) {
+func (m *movieService) GetMovies(ctx context.Context, input *dto.ListMoviesReq) ([]dto.MovieResp, *helper.PaginatedMeta, error) {
+	page, limit := input.Page, input.Limit
 	if page < 1 {
 		page = 1
 	}
@@ -55,13 +74,17 @@ func (m *movieService) GetMovies(ctx context.Context, page, limit int64) ([]dto.
 	}
 
 	offset := (page - 1) * limit
+	filter := input.Filter()
 
-	total, err := m.movieRepository.CountMovies(ctx)
+	total, err := m.movieRepository.CountMovies(ctx, filter)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	movies, err := m.movieRepository.GetMovies(ctx, offset, limit)
+	movies, err := m.movieRepository.GetMovies(ctx, filter, offset, limit)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	response := make([]dto.MovieResp, len(movies))
 	for i, movie := range movies {
@@ -79,7 +102,138 @@ func (m *movieService) GetMovies(ctx context.Context, page, limit int64) ([]dto.
 	return response, meta, nil
 }
 
+func (m *movieService) GetMoviesByCursor(ctx context.Context, input *dto.ListMoviesReq) ([]dto.MovieResp, *helper.CursorMeta, error) {
+	limit := input.Limit
+	if limit < 1 {
+		limit = 10
+	}
+
+	filter := input.Filter()
+	cursor, err := decodeCursor(m.cursors, *input.Cursor, filter.Sort)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	movies, err := m.movieRepository.GetMoviesByCursor(ctx, filter, cursor, limit+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	movies, meta, err := cursorPage(m.cursors, filter.Sort, cursor, movies, limit, func(movie *domain.Movie) (string, string) {
+		return movie.Id, movie.SortKey(filter.Sort)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if input.IncludeTotal {
+		total, err := m.movieRepository.CountMovies(ctx, filter)
+		if err != nil {
+			return nil, nil, err
+		}
+		meta.Total = &total
+	}
+
+	return dto.ToMoviesResp(movies), meta, nil
+}
+
+func (m *movieService) UpdateMovie(ctx context.Context, imdbId string, input *dto.UpdateMovieReq) (*dto.MovieResp, error) {
+	movie, err := m.movieRepository.GetMovie(ctx, imdbId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkVersion(input.Version, movie.Version); err != nil {
+		return nil, err
+	}
+
+	dto.ApplyUpdateMovieReq(movie, input)
+	if input.Ranking != nil {
+		if err := m.checkRanking(ctx, &movie.Ranking); err != nil {
+			return nil, err
+		}
+	}
+
+	movie.UpdatedAt = time.Now()
+
+	if err := m.movieRepository.UpdateMovie(ctx, movie); err != nil {
+		return nil, err
+	}
+
+	return dto.ToMovieResp(movie), nil
+}
+
+func (m *movieService) ReplaceMovie(ctx context.Context, imdbId string, input *dto.ReplaceMovieReq) (*dto.MovieResp, error) {
+	movie, err := m.movieRepository.GetMovie(ctx, imdbId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkVersion(input.Version, movie.Version); err != nil {
+		return nil, err
+	}
+
+	dto.ApplyReplaceMovieReq(movie, input)
+	if err := m.checkRanking(ctx, &movie.Ranking); err != nil {
+		return nil, err
+	}
+
+	movie.UpdatedAt = time.Now()
+
+	if err := m.movieRepository.UpdateMovie(ctx, movie); err != nil {
+		return nil, err
+	}
+
+	return dto.ToMovieResp(movie), nil
+}
+
+// checkRanking makes sure a ranking set by hand is one of the stored rankings,
+// as UpdateAdminReview only ever picks from those.
+func (m *movieService) checkRanking(ctx context.Context, ranking *domain.Ranking) error {
+	stored, err := m.rankingRepository.GetRanking(ctx, ranking.RankingValue)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrUnknownRanking
+		}
+		return err
+	}
+
+	if stored.RankingName != ranking.RankingName {
+		return ErrUnknownRanking
+	}
+	return nil
+}
+
+func (m *movieService) DeleteMovie(ctx context.Context, imdbId string) error {
+	if err := m.movieRepository.DeleteMovie(ctx, imdbId); err != nil {
+		return err
+	}
+
+	if err := m.watchlistRepository.DeleteWatchlistByImdbId(ctx, imdbId); err != nil {
+		return err
+	}
+
+	if err := m.watchHistoryRepository.DeleteWatchHistoryByImdbId(ctx, imdbId); err != nil {
+		return err
+	}
+
+	if err := m.movieListRepository.RemoveMovieFromLists(ctx, imdbId); err != nil {
+		return err
+	}
+
+	return m.reviewRepository.DeleteReviewsByImdbId(ctx, imdbId)
+}
+
 func (m *movieService) UpdateAdminReview(ctx context.Context, imdbId string, input *dto.AdminReviewUpdateReq) (*dto.AdminReviewResp, error) {
+	movie, err := m.movieRepository.GetMovie(ctx, imdbId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkVersion(input.Version, movie.Version); err != nil {
+		return nil, err
+	}
+
 	rankings, err := m.rankingRepository.GetRankings(ctx)
 	if err != nil {
 		return nil, err
@@ -109,19 +263,21 @@ func (m *movieService) UpdateAdminReview(ctx context.Context, imdbId string, inp
 		return nil, errors.New("invalid sentiment ranking")
 	}
 
-	ranking := &domain.Ranking{
+	movie.AdminReview = input.AdminReview
+	movie.Ranking = domain.Ranking{
 		RankingValue: rankVal,
 		RankingName:  sentiment,
 	}
+	movie.UpdatedAt = time.Now()
 
-	err = m.movieRepository.UpdateReview(ctx, imdbId, input.AdminReview, ranking)
-	if err != nil {
+	if err := m.movieRepository.UpdateReview(ctx, movie); err != nil {
 		return nil, err
 	}
 
 	return &dto.AdminReviewResp{
 		RankingName: sentiment,
 		AdminReview: input.AdminReview,
+		Version:     movie.Version,
 	}, nil
 }
 
@@ -152,13 +308,18 @@ func (m *movieService) GetGenres(ctx context.Context) ([]dto.Genre, error) {
 	return dto.ToGenresResp(genres), nil
 }
 
-func NewMovieService(movieRepository repository.MovieRepository, rankingRepository repository.RankingRepository, genreRepository repository.GenreRepository, userRepository repository.UserRepository, openAI AI.OpenAI, config *config.Config) MovieService {
+func NewMovieService(movieRepository repository.MovieRepository, rankingRepository repository.RankingRepository, genreRepository repository.GenreRepository, userRepository repository.UserRepository, reviewRepository repository.ReviewRepository, watchlistRepository repository.WatchlistRepository, watchHistoryRepository repository.WatchHistoryRepository, movieListRepository repository.MovieListRepository, openAI AI.OpenAI, cursors *utils.CursorSigner, config *config.Config) MovieService {
 	return &movieService{
-		movieRepository:   movieRepository,
-		rankingRepository: rankingRepository,
-		genreRepository:   genreRepository,
-		userRepository:    userRepository,
-		openAI:            openAI,
-		config:            config,
+		movieRepository:        movieRepository,
+		rankingRepository:      rankingRepository,
+		genreRepository:        genreRepository,
+		userRepository:         userRepository,
+		reviewRepository:       reviewRepository,
+		watchlistRepository:    watchlistRepository,
+		watchHistoryRepository: watchHistoryRepository,
+		movieListRepository:    movieListRepository,
+		openAI:                 openAI,
+		cursors:                cursors,
+		config:                 config,
 	}
 }
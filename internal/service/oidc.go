@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/saleh-ghazimoradi/Projectopher/infra/oidc"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"strings"
+	"time"
+)
+
+func (a *authService) OIDCAuthorize(ctx context.Context, providerName string) (*dto.OIDCAuthorizeResp, error) {
+	provider, err := a.oidcProviders.Get(providerName)
+	if err != nil {
+		return nil, ErrUnknownOIDCProvider
+	}
+
+	state, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	oidcState := &domain.OIDCState{
+		State:        state,
+		Provider:     providerName,
+		CodeVerifier: oidc.GenerateVerifier(),
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(a.config.OIDC.StateExpires),
+	}
+
+	authorizationURL, err := provider.AuthCodeURL(ctx, state, nonce, oidcState.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.oidcStateRepository.CreateOIDCState(ctx, oidcState); err != nil {
+		return nil, err
+	}
+
+	return &dto.OIDCAuthorizeResp{AuthorizationURL: authorizationURL, State: state, ExpiresAt: oidcState.ExpiresAt}, nil
+}
+
+func (a *authService) OIDCLogin(ctx context.Context, providerName string, input *dto.OIDCCallbackReq, client dto.ClientInfo) (*dto.AuthResp, error) {
+	provider, err := a.oidcProviders.Get(providerName)
+	if err != nil {
+		return nil, ErrUnknownOIDCProvider
+	}
+
+	state, err := a.oidcStateRepository.ConsumeOIDCState(ctx, input.State, providerName)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	identity, err := provider.Exchange(ctx, input.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		a.logger.Warn("oidc login failed", "provider", providerName, "error", err.Error())
+		return nil, ErrOIDCLoginFailed
+	}
+
+	user, err := a.userRepository.GetUserByIdentity(ctx, providerName, identity.Subject)
+	if err != nil {
+		if !errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		user, err = a.linkOIDCIdentity(ctx, providerName, identity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if user.MFA.Enabled {
+		return a.mfaChallenge(user)
+	}
+
+	return a.generateAuthResp(ctx, user, client, nil)
+}
+
+// linkOIDCIdentity attaches identity to the account with the same verified
+// email, or provisions a new account. An unverified local account is never
+// linked: whoever registered it may not own the address.
+func (a *authService) linkOIDCIdentity(ctx context.Context, providerName string, identity *oidc.Identity) (*domain.User, error) {
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, ErrOIDCEmailNotVerified
+	}
+
+	now := time.Now()
+	link := domain.UserIdentity{
+		Provider: providerName,
+		Subject:  identity.Subject,
+		LinkedAt: now,
+	}
+
+	email := domain.NormalizeEmail(identity.Email)
+	user, err := a.userRepository.GetUserByEmail(ctx, email)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	if user != nil {
+		if !user.EmailVerified {
+			return nil, ErrOIDCAccountConflict
+		}
+
+		if err := a.userRepository.AddUserIdentity(ctx, user.Id, &link); err != nil {
+			if errors.Is(err, repository.ErrRecordNotFound) {
+				return nil, ErrOIDCAccountConflict
+			}
+			return nil, err
+		}
+
+		a.logger.Info("oidc identity linked", "user_id", user.Id, "provider", providerName)
+		return user, nil
+	}
+
+	firstName, lastName := identity.GivenName, identity.FamilyName
+	if firstName == "" {
+		firstName, lastName, _ = strings.Cut(identity.Name, " ")
+	}
+	if firstName == "" {
+		firstName, _, _ = strings.Cut(email, "@")
+	}
+
+	user = &domain.User{
+		FirstName:      firstName,
+		LastName:       lastName,
+		Email:          email,
+		Role:           domain.UserRoleUser,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		FavoriteGenres: []domain.Genre{},
+		Version:        1,
+		EmailVerified:  true,
+		VerifiedAt:     &now,
+		Identities:     []domain.UserIdentity{link},
+	}
+
+	if err := a.userRepository.CreateUser(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrDuplicateEmail) {
+			return nil, ErrOIDCAccountConflict
+		}
+		return nil, err
+	}
+
+	a.logger.Info("user provisioned from oidc", "user_id", user.Id, "provider", providerName)
+	return user, nil
+}
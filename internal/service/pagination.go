@@ -0,0 +1,63 @@
+package service
+
+import (
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+)
+
+// decodeCursor returns nil for an empty token, which starts at the first page.
+// A cursor issued for another sort order is rejected rather than reinterpreted.
+func decodeCursor(cursors *utils.CursorSigner, token, sort string) (*domain.Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	var cursor domain.Cursor
+	if err := cursors.Decode(token, &cursor); err != nil || cursor.Id == "" || cursor.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// cursorPage trims rows, read with a limit of limit+1, down to limit and
+// builds the cursors of the neighbouring pages. key returns a row's id and
+// sort key.
+func cursorPage[T any](cursors *utils.CursorSigner, sort string, cursor *domain.Cursor, rows []T, limit int64, key func(*T) (string, string)) ([]T, *helper.CursorMeta, error) {
+	backward := cursor != nil && cursor.Backward
+	hasMore := int64(len(rows)) > limit
+	if hasMore {
+		// The extra row is the one furthest from the cursor.
+		if backward {
+			rows = rows[1:]
+		} else {
+			rows = rows[:limit]
+		}
+	}
+
+	meta := &helper.CursorMeta{Limit: limit}
+	if len(rows) == 0 {
+		return rows, meta, nil
+	}
+
+	encode := func(row *T, backward bool) (string, error) {
+		id, sortKey := key(row)
+		return cursors.Encode(&domain.Cursor{Sort: sort, Key: sortKey, Id: id, Backward: backward})
+	}
+
+	var err error
+	if hasMore || backward {
+		if meta.NextCursor, err = encode(&rows[len(rows)-1], false); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if (hasMore && backward) || (cursor != nil && !backward) {
+		if meta.PrevCursor, err = encode(&rows[0], true); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return rows, meta, nil
+}
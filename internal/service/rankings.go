@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+)
+
+type RankingService interface {
+	CreateRanking(ctx context.Context, input *dto.CreateRankingReq) (*dto.Ranking, error)
+	GetRanking(ctx context.Context, rankingValue int) (*dto.Ranking, error)
+	GetRankings(ctx context.Context) ([]dto.Ranking, error)
+	UpdateRanking(ctx context.Context, rankingValue int, input *dto.UpdateRankingReq) (*dto.Ranking, error)
+	DeleteRanking(ctx context.Context, rankingValue int, reassignTo *int) error
+}
+
+type rankingService struct {
+	rankingRepository repository.RankingRepository
+	movieRepository   repository.MovieRepository
+}
+
+func (r *rankingService) CreateRanking(ctx context.Context, input *dto.CreateRankingReq) (*dto.Ranking, error) {
+	ranking := &domain.Ranking{
+		RankingValue: input.RankingValue,
+		RankingName:  input.RankingName,
+	}
+
+	if err := r.rankingRepository.CreateRanking(ctx, ranking); err != nil {
+		return nil, err
+	}
+
+	return dto.ToRankingResp(ranking), nil
+}
+
+func (r *rankingService) GetRanking(ctx context.Context, rankingValue int) (*dto.Ranking, error) {
+	ranking, err := r.rankingRepository.GetRanking(ctx, rankingValue)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToRankingResp(ranking), nil
+}
+
+func (r *rankingService) GetRankings(ctx context.Context) ([]dto.Ranking, error) {
+	rankings, err := r.rankingRepository.GetRankings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToRankingsResp(rankings), nil
+}
+
+func (r *rankingService) UpdateRanking(ctx context.Context, rankingValue int, input *dto.UpdateRankingReq) (*dto.Ranking, error) {
+	ranking, err := r.rankingRepository.GetRanking(ctx, rankingValue)
+	if err != nil {
+		return nil, err
+	}
+
+	ranking.RankingName = *input.RankingName
+
+	if err := r.rankingRepository.UpdateRanking(ctx, ranking); err != nil {
+		return nil, err
+	}
+
+	if err := r.movieRepository.RenameRanking(ctx, ranking); err != nil {
+		return nil, err
+	}
+
+	return dto.ToRankingResp(ranking), nil
+}
+
+func (r *rankingService) DeleteRanking(ctx context.Context, rankingValue int, reassignTo *int) error {
+	if _, err := r.rankingRepository.GetRanking(ctx, rankingValue); err != nil {
+		return err
+	}
+
+	if reassignTo == nil {
+		count, err := r.movieRepository.CountMoviesByRanking(ctx, rankingValue)
+		if err != nil {
+			return err
+		}
+
+		if count > 0 {
+			return ErrRankingInUse
+		}
+
+		return r.rankingRepository.DeleteRanking(ctx, rankingValue)
+	}
+
+	if *reassignTo == rankingValue {
+		return ErrInvalidReassignTarget
+	}
+
+	target, err := r.rankingRepository.GetRanking(ctx, *reassignTo)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrInvalidReassignTarget
+		}
+		return err
+	}
+
+	if err := r.movieRepository.ReassignRanking(ctx, rankingValue, target); err != nil {
+		return err
+	}
+
+	return r.rankingRepository.DeleteRanking(ctx, rankingValue)
+}
+
+func NewRankingService(rankingRepository repository.RankingRepository, movieRepository repository.MovieRepository) RankingService {
+	return &rankingService{
+		rankingRepository: rankingRepository,
+		movieRepository:   movieRepository,
+	}
+}
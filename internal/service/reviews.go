@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"log/slog"
+	"time"
+)
+
+type ReviewService interface {
+	CreateReview(ctx context.Context, imdbId, userId string, input *dto.CreateReviewReq) (*dto.ReviewResp, error)
+	GetReviews(ctx context.Context, imdbId, token string, limit int64, includeTotal bool) ([]dto.ReviewResp, *helper.CursorMeta, error)
+	UpdateReview(ctx context.Context, imdbId, userId string, input *dto.UpdateReviewReq) (*dto.ReviewResp, error)
+	DeleteReview(ctx context.Context, imdbId, userId string) error
+}
+
+type reviewService struct {
+	reviewRepository repository.ReviewRepository
+	movieRepository  repository.MovieRepository
+	cursors          *utils.CursorSigner
+	logger           *slog.Logger
+}
+
+func (r *reviewService) CreateReview(ctx context.Context, imdbId, userId string, input *dto.CreateReviewReq) (*dto.ReviewResp, error) {
+	if _, err := r.movieRepository.GetMovie(ctx, imdbId); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	review := &domain.Review{
+		ImdbId:    imdbId,
+		UserId:    userId,
+		Rating:    input.Rating,
+		Body:      input.Body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := r.reviewRepository.CreateReview(ctx, review); err != nil {
+		return nil, err
+	}
+
+	// DeleteMovie removes the movie before its reviews, so a review inserted
+	// while the movie was being deleted is either swept up with the others or
+	// caught here and taken back.
+	if _, err := r.movieRepository.GetMovie(ctx, imdbId); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			if _, err := r.reviewRepository.DeleteReview(ctx, imdbId, userId); err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	if err := r.reviewRepository.RefreshMovieRating(ctx, imdbId); err != nil {
+		return nil, err
+	}
+
+	return dto.ToReviewResp(review), nil
+}
+
+func (r *reviewService) GetReviews(ctx context.Context, imdbId, token string, limit int64, includeTotal bool) ([]dto.ReviewResp, *helper.CursorMeta, error) {
+	if limit < 1 {
+		limit = 10
+	}
+
+	cursor, err := decodeCursor(r.cursors, token, domain.ReviewSortNewest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := r.movieRepository.GetMovie(ctx, imdbId); err != nil {
+		return nil, nil, err
+	}
+
+	reviews, err := r.reviewRepository.GetReviewsByCursor(ctx, imdbId, cursor, limit+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reviews, meta, err := cursorPage(r.cursors, domain.ReviewSortNewest, cursor, reviews, limit, func(review *domain.Review) (string, string) {
+		return review.Id, review.SortKey()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if includeTotal {
+		total, err := r.reviewRepository.CountReviews(ctx, imdbId)
+		if err != nil {
+			return nil, nil, err
+		}
+		meta.Total = &total
+	}
+
+	return dto.ToReviewsResp(reviews), meta, nil
+}
+
+func (r *reviewService) UpdateReview(ctx context.Context, imdbId, userId string, input *dto.UpdateReviewReq) (*dto.ReviewResp, error) {
+	review, err := r.reviewRepository.GetReview(ctx, imdbId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	dto.ApplyUpdateReviewReq(review, input)
+	review.UpdatedAt = time.Now()
+
+	previous, err := r.reviewRepository.UpdateReview(ctx, review)
+	if err != nil {
+		return nil, err
+	}
+
+	if review.Rating != previous.Rating {
+		if err := r.reviewRepository.RefreshMovieRating(ctx, imdbId); err != nil {
+			return nil, err
+		}
+	}
+
+	return dto.ToReviewResp(review), nil
+}
+
+func (r *reviewService) DeleteReview(ctx context.Context, imdbId, userId string) error {
+	if _, err := r.reviewRepository.DeleteReview(ctx, imdbId, userId); err != nil {
+		return err
+	}
+
+	return r.reviewRepository.RefreshMovieRating(ctx, imdbId)
+}
+
+// deleteUserReviews removes every review by userId and recounts the ratings of
+// the movies they were counted in.
+func deleteUserReviews(ctx context.Context, reviewRepository repository.ReviewRepository, userId string) error {
+	reviews, err := reviewRepository.GetReviewsByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	for _, review := range reviews {
+		if _, err := reviewRepository.DeleteReview(ctx, review.ImdbId, userId); err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+			return err
+		}
+
+		if err := reviewRepository.RefreshMovieRating(ctx, review.ImdbId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func NewReviewService(reviewRepository repository.ReviewRepository, movieRepository repository.MovieRepository, cursors *utils.CursorSigner, logger *slog.Logger) ReviewService {
+	return &reviewService{
+		reviewRepository: reviewRepository,
+		movieRepository:  movieRepository,
+		cursors:          cursors,
+		logger:           logger,
+	}
+}
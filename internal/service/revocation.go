@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/authz"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"time"
+)
+
+// revokeUserAccessTokens denylists every access token of userId that has not
+// expired yet, so deletions and role changes apply to tokens already handed out.
+func revokeUserAccessTokens(ctx context.Context, tokenRepository repository.TokenRepository, denylist *authz.Denylist, userId string) error {
+	tokens, err := tokenRepository.GetLiveAccessTokensByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+	return denylist.Revoke(ctx, toRevokedTokens(tokens)...)
+}
+
+func revokeFamilyAccessTokens(ctx context.Context, tokenRepository repository.TokenRepository, denylist *authz.Denylist, familyId string) error {
+	tokens, err := tokenRepository.GetLiveAccessTokensByFamilyId(ctx, familyId)
+	if err != nil {
+		return err
+	}
+	return denylist.Revoke(ctx, toRevokedTokens(tokens)...)
+}
+
+func toRevokedTokens(tokens []domain.RefreshToken) []domain.RevokedToken {
+	now := time.Now()
+	revoked := make([]domain.RevokedToken, 0, len(tokens))
+	for _, token := range tokens {
+		if token.AccessTokenId == "" {
+			continue
+		}
+		revoked = append(revoked, domain.RevokedToken{
+			Jti:       token.AccessTokenId,
+			UserId:    token.UserId,
+			ExpiresAt: token.AccessExpiresAt,
+			RevokedAt: now,
+		})
+	}
+	return revoked
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/authz"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"time"
+)
+
+type RoleService interface {
+	GetRoles(ctx context.Context) ([]dto.RoleResp, error)
+	UpdateRolePermissions(ctx context.Context, name string, input *dto.UpdateRolePermissionsReq) (*dto.RoleResp, error)
+}
+
+type roleService struct {
+	roleRepository  repository.RoleRepository
+	permissionCache *authz.PermissionCache
+}
+
+func (r *roleService) GetRoles(ctx context.Context) ([]dto.RoleResp, error) {
+	roles, err := r.roleRepository.GetRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToRolesResp(roles), nil
+}
+
+func (r *roleService) UpdateRolePermissions(ctx context.Context, name string, input *dto.UpdateRolePermissionsReq) (*dto.RoleResp, error) {
+	role, err := r.roleRepository.GetRole(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	role.Permissions = make([]domain.Permission, len(input.Permissions))
+	for i, p := range input.Permissions {
+		role.Permissions[i] = domain.Permission(p)
+	}
+	role.UpdatedAt = time.Now()
+
+	if err := r.roleRepository.UpdateRolePermissions(ctx, role); err != nil {
+		return nil, err
+	}
+
+	r.permissionCache.Invalidate()
+
+	return dto.ToRoleResp(role), nil
+}
+
+func NewRoleService(roleRepository repository.RoleRepository, permissionCache *authz.PermissionCache) RoleService {
+	return &roleService{
+		roleRepository:  roleRepository,
+		permissionCache: permissionCache,
+	}
+}
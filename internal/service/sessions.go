@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/authz"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"log/slog"
+)
+
+type SessionService interface {
+	GetSessions(ctx context.Context, userId string) ([]dto.SessionResp, error)
+	RevokeSession(ctx context.Context, userId, sessionId string) error
+	RevokeAllSessions(ctx context.Context, userId string) error
+}
+
+type sessionService struct {
+	userRepository  repository.UserRepository
+	tokenRepository repository.TokenRepository
+	denylist        *authz.Denylist
+	logger          *slog.Logger
+}
+
+func (s *sessionService) GetSessions(ctx context.Context, userId string) ([]dto.SessionResp, error) {
+	tokens, err := s.tokenRepository.GetActiveRefreshTokensByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]dto.SessionResp, len(tokens))
+	for i, token := range tokens {
+		sessions[i] = dto.SessionResp{
+			Id:         token.FamilyId,
+			UserAgent:  token.UserAgent,
+			IP:         token.IP,
+			CreatedAt:  token.SessionCreatedAt,
+			LastUsedAt: token.LastUsedAt,
+			ExpiresAt:  token.ExpiresAt,
+		}
+	}
+
+	return sessions, nil
+}
+
+func (s *sessionService) RevokeSession(ctx context.Context, userId, sessionId string) error {
+	tokens, err := s.tokenRepository.GetLiveAccessTokensByFamilyId(ctx, sessionId)
+	if err != nil {
+		return err
+	}
+
+	owned := tokens[:0]
+	for _, token := range tokens {
+		if token.UserId == userId {
+			owned = append(owned, token)
+		}
+	}
+
+	if err := s.denylist.Revoke(ctx, toRevokedTokens(owned)...); err != nil {
+		return err
+	}
+
+	return s.tokenRepository.DeleteUserRefreshTokenFamily(ctx, userId, sessionId)
+}
+
+func (s *sessionService) RevokeAllSessions(ctx context.Context, userId string) error {
+	if _, err := s.userRepository.GetUserById(ctx, userId); err != nil {
+		return err
+	}
+
+	if err := revokeUserAccessTokens(ctx, s.tokenRepository, s.denylist, userId); err != nil {
+		return err
+	}
+
+	if err := s.tokenRepository.DeleteRefreshTokensByUserId(ctx, userId); err != nil {
+		return err
+	}
+
+	s.logger.Info("all sessions revoked", "user_id", userId)
+	return nil
+}
+
+func NewSessionService(userRepository repository.UserRepository, tokenRepository repository.TokenRepository, denylist *authz.Denylist, logger *slog.Logger) SessionService {
+	return &sessionService{
+		userRepository:  userRepository,
+		tokenRepository: tokenRepository,
+		denylist:        denylist,
+		logger:          logger,
+	}
+}
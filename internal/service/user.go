@@ -2,21 +2,43 @@ package service
 
 import (
 	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/authz"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
 	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"log/slog"
+	"time"
 )
 
 type UserService interface {
 	GetProfile(ctx context.Context, id string) (*dto.UserResp, error)
 	GetProfiles(ctx context.Context, page, limit int64) ([]dto.UserResp, *helper.PaginatedMeta, error)
+	GetProfilesByCursor(ctx context.Context, token string, limit int64, includeTotal bool) ([]dto.UserResp, *helper.CursorMeta, error)
 	UpdateProfile(ctx context.Context, id string, input *dto.UpdateUserReq) (*dto.UserResp, error)
 	DeleteProfile(ctx context.Context, id string) error
+	AssignRole(ctx context.Context, id string, input *dto.AssignRoleReq) (*dto.UserResp, error)
+	ChangePassword(ctx context.Context, id string, input *dto.ChangePasswordReq) error
+	GetLoginLock(ctx context.Context, id string) (*dto.LoginLockResp, error)
+	UnlockLogin(ctx context.Context, id string) error
 }
 
 type userService struct {
-	userRepository repository.UserRepository
+	userRepository         repository.UserRepository
+	roleRepository         repository.RoleRepository
+	tokenRepository        repository.TokenRepository
+	loginAttemptRepository repository.LoginAttemptRepository
+	apiKeyRepository       repository.APIKeyRepository
+	reviewRepository       repository.ReviewRepository
+	watchlistRepository    repository.WatchlistRepository
+	watchHistoryRepository repository.WatchHistoryRepository
+	movieListRepository    repository.MovieListRepository
+	passwordHasher         utils.PasswordHasher
+	cursors                *utils.CursorSigner
+	denylist               *authz.Denylist
+	logger                 *slog.Logger
 }
 
 func (u *userService) GetProfile(ctx context.Context, id string) (*dto.UserResp, error) {
@@ -64,12 +86,54 @@ func (u *userService) GetProfiles(ctx context.Context, page, limit int64) ([]dto
 	return response, meta, nil
 }
 
+func (u *userService) GetProfilesByCursor(ctx context.Context, token string, limit int64, includeTotal bool) ([]dto.UserResp, *helper.CursorMeta, error) {
+	if limit < 1 {
+		limit = 10
+	}
+
+	cursor, err := decodeCursor(u.cursors, token, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	users, err := u.userRepository.GetUsersByCursor(ctx, cursor, limit+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	users, meta, err := cursorPage(u.cursors, "", cursor, users, limit, func(user *domain.User) (string, string) {
+		return user.Id, ""
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if includeTotal {
+		total, err := u.userRepository.CountUser(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		meta.Total = &total
+	}
+
+	response := make([]dto.UserResp, len(users))
+	for i := range users {
+		response[i] = *u.toUser(&users[i])
+	}
+
+	return response, meta, nil
+}
+
 func (u *userService) UpdateProfile(ctx context.Context, id string, input *dto.UpdateUserReq) (*dto.UserResp, error) {
 	user, err := u.userRepository.GetUserById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := checkVersion(input.Version, user.Version); err != nil {
+		return nil, err
+	}
+
 	if input.FirstName != nil {
 		user.FirstName = *input.FirstName
 	}
@@ -78,6 +142,8 @@ func (u *userService) UpdateProfile(ctx context.Context, id string, input *dto.U
 		user.LastName = *input.LastName
 	}
 
+	user.UpdatedAt = time.Now()
+
 	if err := u.userRepository.UpdateUser(ctx, user); err != nil {
 		return nil, err
 	}
@@ -86,7 +152,145 @@ func (u *userService) UpdateProfile(ctx context.Context, id string, input *dto.U
 }
 
 func (u *userService) DeleteProfile(ctx context.Context, id string) error {
-	return u.userRepository.DeleteUser(ctx, id)
+	if err := u.userRepository.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+
+	if err := revokeUserAccessTokens(ctx, u.tokenRepository, u.denylist, id); err != nil {
+		return err
+	}
+
+	if err := u.tokenRepository.DeleteRefreshTokensByUserId(ctx, id); err != nil {
+		return err
+	}
+
+	if err := u.apiKeyRepository.DeleteAPIKeysByUserId(ctx, id); err != nil {
+		return err
+	}
+
+	if err := u.watchlistRepository.DeleteWatchlistByUserId(ctx, id); err != nil {
+		return err
+	}
+
+	if err := u.watchHistoryRepository.DeleteWatchHistoryByUserId(ctx, id); err != nil {
+		return err
+	}
+
+	if err := u.movieListRepository.DeleteMovieListsByOwner(ctx, id); err != nil {
+		return err
+	}
+
+	return deleteUserReviews(ctx, u.reviewRepository, id)
+}
+
+func (u *userService) AssignRole(ctx context.Context, id string, input *dto.AssignRoleReq) (*dto.UserResp, error) {
+	if _, err := u.roleRepository.GetRole(ctx, input.Role); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, ErrUnknownRole
+		}
+		return nil, err
+	}
+
+	user, err := u.userRepository.GetUserById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkVersion(input.Version, user.Version); err != nil {
+		return nil, err
+	}
+
+	previousRole := user.Role
+	user.Role = domain.UserRole(input.Role)
+	user.UpdatedAt = time.Now()
+
+	if err := u.userRepository.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// Outstanding access tokens still carry the old role's permissions. Refresh
+	// tokens stay valid since refreshing reloads the role from the database.
+	if previousRole != user.Role {
+		if err := revokeUserAccessTokens(ctx, u.tokenRepository, u.denylist, user.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	return u.toUser(user), nil
+}
+
+func (u *userService) ChangePassword(ctx context.Context, id string, input *dto.ChangePasswordReq) error {
+	user, err := u.userRepository.GetUserById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	ok, err := u.passwordHasher.Verify(user.Password, input.CurrentPassword)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrIncorrectPassword
+	}
+
+	hashedPassword, err := u.passwordHasher.Hash(input.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	user.Password = hashedPassword
+	user.UpdatedAt = time.Now()
+
+	if err := u.userRepository.UpdatePassword(ctx, user); err != nil {
+		return err
+	}
+
+	if err := revokeUserAccessTokens(ctx, u.tokenRepository, u.denylist, user.Id); err != nil {
+		return err
+	}
+
+	if err := u.tokenRepository.DeleteRefreshTokensByUserId(ctx, user.Id); err != nil {
+		return err
+	}
+
+	return u.apiKeyRepository.DeleteAPIKeysByUserId(ctx, user.Id)
+}
+
+func (u *userService) GetLoginLock(ctx context.Context, id string) (*dto.LoginLockResp, error) {
+	user, err := u.userRepository.GetUserById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt, err := u.loginAttemptRepository.GetLoginAttempt(ctx, accountAttemptKey(user.Email))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return &dto.LoginLockResp{}, nil
+		}
+		return nil, err
+	}
+
+	return &dto.LoginLockResp{
+		Failures:      attempt.Failures,
+		Locked:        attempt.LockedUntil != nil && attempt.LockedUntil.After(time.Now()),
+		LockedUntil:   attempt.LockedUntil,
+		LastFailureAt: &attempt.LastFailureAt,
+	}, nil
+}
+
+func (u *userService) UnlockLogin(ctx context.Context, id string) error {
+	user, err := u.userRepository.GetUserById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := u.loginAttemptRepository.DeleteLoginAttempt(ctx, accountAttemptKey(user.Email)); err != nil {
+		return err
+	}
+
+	unlockedBy, _ := utils.UserIdFromCtx(ctx)
+	u.logger.Info("account unlocked", "user_id", user.Id, "unlocked_by", unlockedBy)
+	return nil
 }
 
 func (u *userService) toUser(user *domain.User) *dto.UserResp {
@@ -107,11 +311,26 @@ func (u *userService) toUser(user *domain.User) *dto.UserResp {
 		CreatedAt:      user.CreatedAt,
 		UpdatedAt:      user.UpdatedAt,
 		FavoriteGenres: genres,
+		Version:        user.Version,
+		EmailVerified:  user.EmailVerified,
+		MFAEnabled:     user.MFA.Enabled,
 	}
 }
 
-func NewUserService(userRepository repository.UserRepository) UserService {
+func NewUserService(userRepository repository.UserRepository, roleRepository repository.RoleRepository, tokenRepository repository.TokenRepository, loginAttemptRepository repository.LoginAttemptRepository, apiKeyRepository repository.APIKeyRepository, reviewRepository repository.ReviewRepository, watchlistRepository repository.WatchlistRepository, watchHistoryRepository repository.WatchHistoryRepository, movieListRepository repository.MovieListRepository, passwordHasher utils.PasswordHasher, cursors *utils.CursorSigner, denylist *authz.Denylist, logger *slog.Logger) UserService {
 	return &userService{
-		userRepository: userRepository,
+		userRepository:         userRepository,
+		roleRepository:         roleRepository,
+		tokenRepository:        tokenRepository,
+		loginAttemptRepository: loginAttemptRepository,
+		apiKeyRepository:       apiKeyRepository,
+		reviewRepository:       reviewRepository,
+		watchlistRepository:    watchlistRepository,
+		watchHistoryRepository: watchHistoryRepository,
+		movieListRepository:    movieListRepository,
+		passwordHasher:         passwordHasher,
+		cursors:                cursors,
+		denylist:               denylist,
+		logger:                 logger,
 	}
 }
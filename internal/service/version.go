@@ -0,0 +1,10 @@
+package service
+
+import "github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+
+func checkVersion(expected *int64, current int64) error {
+	if expected != nil && *expected != current {
+		return repository.ErrEditConflict
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/domain"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/dto"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/helper"
+	"github.com/saleh-ghazimoradi/Projectopher/internal/repository"
+	"github.com/saleh-ghazimoradi/Projectopher/utils"
+	"log/slog"
+	"time"
+)
+
+type WatchlistService interface {
+	AddToWatchlist(ctx context.Context, userId string, input *dto.AddWatchlistItemReq) (*dto.WatchlistItemResp, error)
+	GetWatchlist(ctx context.Context, userId, token string, limit int64, includeTotal bool) ([]dto.WatchlistItemResp, *helper.CursorMeta, error)
+	RemoveFromWatchlist(ctx context.Context, userId, imdbId string) error
+	AddToHistory(ctx context.Context, userId string, input *dto.AddWatchHistoryReq) (*dto.WatchHistoryEntryResp, error)
+	GetHistory(ctx context.Context, userId, token string, limit int64, includeTotal bool) ([]dto.WatchHistoryEntryResp, *helper.CursorMeta, error)
+	DeleteHistoryEntry(ctx context.Context, userId, id string) error
+}
+
+type watchlistService struct {
+	watchlistRepository    repository.WatchlistRepository
+	watchHistoryRepository repository.WatchHistoryRepository
+	movieRepository        repository.MovieRepository
+	cursors                *utils.CursorSigner
+	logger                 *slog.Logger
+}
+
+func (w *watchlistService) AddToWatchlist(ctx context.Context, userId string, input *dto.AddWatchlistItemReq) (*dto.WatchlistItemResp, error) {
+	movie, err := w.movieRepository.GetMovie(ctx, input.ImdbId)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &domain.WatchlistItem{
+		UserId:  userId,
+		ImdbId:  input.ImdbId,
+		AddedAt: time.Now(),
+	}
+
+	if err := w.watchlistRepository.CreateWatchlistItem(ctx, item); err != nil {
+		return nil, err
+	}
+
+	item.Movie = movie
+	return dto.ToWatchlistItemResp(item), nil
+}
+
+func (w *watchlistService) GetWatchlist(ctx context.Context, userId, token string, limit int64, includeTotal bool) ([]dto.WatchlistItemResp, *helper.CursorMeta, error) {
+	if limit < 1 {
+		limit = 10
+	}
+
+	cursor, err := decodeCursor(w.cursors, token, domain.WatchlistSortNewest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, err := w.watchlistRepository.GetWatchlistByCursor(ctx, userId, cursor, limit+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, meta, err := cursorPage(w.cursors, domain.WatchlistSortNewest, cursor, items, limit, func(item *domain.WatchlistItem) (string, string) {
+		return item.Id, item.SortKey()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if includeTotal {
+		total, err := w.watchlistRepository.CountWatchlist(ctx, userId)
+		if err != nil {
+			return nil, nil, err
+		}
+		meta.Total = &total
+	}
+
+	return dto.ToWatchlistResp(items), meta, nil
+}
+
+func (w *watchlistService) RemoveFromWatchlist(ctx context.Context, userId, imdbId string) error {
+	return w.watchlistRepository.DeleteWatchlistItem(ctx, userId, imdbId)
+}
+
+// AddToHistory logs a viewing and takes the movie off the watchlist, since it
+// has now been seen.
+func (w *watchlistService) AddToHistory(ctx context.Context, userId string, input *dto.AddWatchHistoryReq) (*dto.WatchHistoryEntryResp, error) {
+	movie, err := w.movieRepository.GetMovie(ctx, input.ImdbId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entry := &domain.WatchHistoryEntry{
+		UserId:    userId,
+		ImdbId:    input.ImdbId,
+		WatchedAt: now,
+		Rating:    input.Rating,
+		CreatedAt: now,
+	}
+	if input.WatchedAt != nil {
+		entry.WatchedAt = *input.WatchedAt
+	}
+
+	if err := w.watchHistoryRepository.CreateWatchHistoryEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	if err := w.watchlistRepository.DeleteWatchlistItem(ctx, userId, input.ImdbId); err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		w.logger.Warn("failed to remove watched movie from watchlist", "user_id", userId, "imdb_id", input.ImdbId, "error", err.Error())
+	}
+
+	entry.Movie = movie
+	return dto.ToWatchHistoryEntryResp(entry), nil
+}
+
+func (w *watchlistService) GetHistory(ctx context.Context, userId, token string, limit int64, includeTotal bool) ([]dto.WatchHistoryEntryResp, *helper.CursorMeta, error) {
+	if limit < 1 {
+		limit = 10
+	}
+
+	cursor, err := decodeCursor(w.cursors, token, domain.HistorySortNewest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, err := w.watchHistoryRepository.GetWatchHistoryByCursor(ctx, userId, cursor, limit+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, meta, err := cursorPage(w.cursors, domain.HistorySortNewest, cursor, entries, limit, func(entry *domain.WatchHistoryEntry) (string, string) {
+		return entry.Id, entry.SortKey()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if includeTotal {
+		total, err := w.watchHistoryRepository.CountWatchHistory(ctx, userId)
+		if err != nil {
+			return nil, nil, err
+		}
+		meta.Total = &total
+	}
+
+	return dto.ToWatchHistoryResp(entries), meta, nil
+}
+
+func (w *watchlistService) DeleteHistoryEntry(ctx context.Context, userId, id string) error {
+	return w.watchHistoryRepository.DeleteWatchHistoryEntry(ctx, userId, id)
+}
+
+func NewWatchlistService(watchlistRepository repository.WatchlistRepository, watchHistoryRepository repository.WatchHistoryRepository, movieRepository repository.MovieRepository, cursors *utils.CursorSigner, logger *slog.Logger) WatchlistService {
+	return &watchlistService{
+		watchlistRepository:    watchlistRepository,
+		watchHistoryRepository: watchHistoryRepository,
+		movieRepository:        movieRepository,
+		cursors:                cursors,
+		logger:                 logger,
+	}
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientIPResolver finds the address a request came from. Forwarding headers
+// are only believed when the connection comes from a trusted proxy, otherwise
+// any client could choose the address its rate limit and lockout are keyed on.
+type ClientIPResolver struct {
+	trusted []netip.Prefix
+}
+
+// ClientIP returns the client address of r. X-Forwarded-For is read right to
+// left and the first hop that is not a trusted proxy wins.
+func (c *ClientIPResolver) ClientIP(r *http.Request) string {
+	remote, ok := parseAddr(r.RemoteAddr)
+	if !ok {
+		return r.RemoteAddr
+	}
+	if !c.isTrusted(remote) {
+		return remote.String()
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, ok := parseAddr(strings.TrimSpace(hops[i]))
+		if !ok {
+			break
+		}
+		if !c.isTrusted(hop) {
+			return hop.String()
+		}
+		remote = hop
+	}
+
+	if realIP, ok := parseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ok {
+		return realIP.String()
+	}
+	return remote.String()
+}
+
+func (c *ClientIPResolver) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range c.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseAddr(value string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+// NewClientIPResolver trusts forwarding headers from proxies, given as
+// addresses or CIDR ranges.
+func NewClientIPResolver(proxies []string) (*ClientIPResolver, error) {
+	resolver := &ClientIPResolver{}
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			resolver.trusted = append(resolver.trusted, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		addr = addr.Unmap()
+		resolver.trusted = append(resolver.trusted, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return resolver, nil
+}
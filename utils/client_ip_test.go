@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIPResolver(t *testing.T) {
+	resolver, err := NewClientIPResolver([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		realIP     string
+		want       string
+	}{
+		{
+			name:       "direct client",
+			remoteAddr: "203.0.113.7:4000",
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "untrusted peer cannot spoof forwarded for",
+			remoteAddr: "203.0.113.7:4000",
+			forwarded:  []string{"198.51.100.1"},
+			realIP:     "198.51.100.2",
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "trusted proxy",
+			remoteAddr: "10.1.2.3:4000",
+			forwarded:  []string{"198.51.100.1"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "spoofed hop left of the real client is ignored",
+			remoteAddr: "10.1.2.3:4000",
+			forwarded:  []string{"1.1.1.1, 198.51.100.1"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "chain of trusted proxies",
+			remoteAddr: "10.1.2.3:4000",
+			forwarded:  []string{"198.51.100.1, 192.0.2.1", "10.9.9.9"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "trusted proxy with x-real-ip",
+			remoteAddr: "192.0.2.1:4000",
+			realIP:     "198.51.100.3",
+			want:       "198.51.100.3",
+		},
+		{
+			name:       "trusted proxy without headers",
+			remoteAddr: "10.1.2.3:4000",
+			want:       "10.1.2.3",
+		},
+		{
+			name:       "ipv4 mapped ipv6 peer",
+			remoteAddr: "[::ffff:10.1.2.3]:4000",
+			forwarded:  []string{"198.51.100.1"},
+			want:       "198.51.100.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			if got := resolver.ClientIP(r); got != tt.want {
+				t.Fatalf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClientIPResolverRejectsInvalidProxy(t *testing.T) {
+	for _, proxy := range []string{"not-an-ip", "10.0.0.0/33"} {
+		if _, err := NewClientIPResolver([]string{proxy}); err == nil {
+			t.Errorf("NewClientIPResolver(%q) succeeded, want an error", proxy)
+		}
+	}
+}
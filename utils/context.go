@@ -5,11 +5,14 @@ import "context"
 type ContextKey string
 
 const (
-	FirstNameKey ContextKey = "first_name"
-	LastNameKey  ContextKey = "last_name"
-	EmailKey     ContextKey = "email"
-	RoleKey      ContextKey = "role"
-	UserIdKey    ContextKey = "user_id"
+	FirstNameKey   ContextKey = "first_name"
+	LastNameKey    ContextKey = "last_name"
+	EmailKey       ContextKey = "email"
+	RoleKey        ContextKey = "role"
+	UserIdKey      ContextKey = "user_id"
+	PermissionsKey ContextKey = "permissions"
+	APIKeyIdKey    ContextKey = "api_key_id"
+	ClientIPKey    ContextKey = "client_ip"
 )
 
 func WithFirstName(ctx context.Context, firstName string) context.Context {
@@ -32,6 +35,18 @@ func WithUserId(ctx context.Context, userId string) context.Context {
 	return context.WithValue(ctx, UserIdKey, userId)
 }
 
+func WithPermissions(ctx context.Context, permissions []string) context.Context {
+	return context.WithValue(ctx, PermissionsKey, permissions)
+}
+
+func WithAPIKeyId(ctx context.Context, apiKeyId string) context.Context {
+	return context.WithValue(ctx, APIKeyIdKey, apiKeyId)
+}
+
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, ClientIPKey, ip)
+}
+
 func FirstNameFromCtx(ctx context.Context) (string, bool) {
 	firstName, ok := ctx.Value(FirstNameKey).(string)
 	return firstName, ok
@@ -56,3 +71,20 @@ func UserIdFromCtx(ctx context.Context) (string, bool) {
 	userId, ok := ctx.Value(UserIdKey).(string)
 	return userId, ok
 }
+
+func PermissionsFromCtx(ctx context.Context) ([]string, bool) {
+	permissions, ok := ctx.Value(PermissionsKey).([]string)
+	return permissions, ok
+}
+
+// APIKeyIdFromCtx reports whether the request was authenticated with an API
+// key rather than an access token.
+func APIKeyIdFromCtx(ctx context.Context) (string, bool) {
+	apiKeyId, ok := ctx.Value(APIKeyIdKey).(string)
+	return apiKeyId, ok
+}
+
+func ClientIPFromCtx(ctx context.Context) (string, bool) {
+	ip, ok := ctx.Value(ClientIPKey).(string)
+	return ip, ok
+}
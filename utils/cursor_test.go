@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testCursor struct {
+	Sort     string `json:"s"`
+	Key      string `json:"k"`
+	Id       string `json:"i"`
+	Backward bool   `json:"b"`
+}
+
+func TestCursorSignerRoundTrip(t *testing.T) {
+	signer, err := NewCursorSigner("cursor-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		cursor testCursor
+	}{
+		{name: "forward", cursor: testCursor{Sort: "title", Key: "Alien", Id: "65f1c0ffee0000000000abcd"}},
+		{name: "backward", cursor: testCursor{Sort: "-created_at", Key: "2024-01-02T03:04:05Z", Id: "65f1c0ffee0000000000abcd", Backward: true}},
+		{name: "key with separator", cursor: testCursor{Sort: "title", Key: "a.b.c", Id: "65f1c0ffee0000000000abcd"}},
+		{name: "empty", cursor: testCursor{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := signer.Encode(tt.cursor)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+
+			var got testCursor
+			if err := signer.Decode(token, &got); err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if got != tt.cursor {
+				t.Fatalf("Decode = %+v, want %+v", got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestCursorSignerRejectsTampering(t *testing.T) {
+	signer, err := NewCursorSigner("cursor-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewCursorSigner("another-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := signer.Encode(testCursor{Sort: "title", Key: "Alien", Id: "65f1c0ffee0000000000abcd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, signature, _ := strings.Cut(token, ".")
+
+	forged, err := other.Encode(testCursor{Sort: "title", Key: "Zulu", Id: "65f1c0ffee0000000000abcd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "missing signature", token: encoded},
+		{name: "empty signature", token: encoded + "."},
+		{name: "swapped payload", token: forgedPayload + "." + signature},
+		{name: "signed with another secret", token: forged},
+		{name: "flipped signature byte", token: encoded + "." + flipLastChar(signature)},
+		{name: "flipped payload byte", token: flipLastChar(encoded) + "." + signature},
+		{name: "payload not base64", token: "!!!." + signer.sign("!!!")},
+		{name: "payload not json", token: base64.RawURLEncoding.EncodeToString([]byte("nope")) + "." + signer.sign(base64.RawURLEncoding.EncodeToString([]byte("nope")))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got testCursor
+			if err := signer.Decode(tt.token, &got); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("Decode error = %v, want %v", err, ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestNewCursorSignerWithoutSecret(t *testing.T) {
+	first, err := NewCursorSigner("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewCursorSigner("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := first.Encode(testCursor{Key: "Alien"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got testCursor
+	if err := first.Decode(token, &got); err != nil {
+		t.Fatalf("Decode with the same signer: %v", err)
+	}
+	if err := second.Decode(token, &got); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("Decode with another random secret = %v, want %v", err, ErrInvalidCursor)
+	}
+}
+
+func flipLastChar(s string) string {
+	last := s[len(s)-1]
+	replacement := byte('A')
+	if last == 'A' {
+		replacement = 'B'
+	}
+	return s[:len(s)-1] + string(replacement)
+}
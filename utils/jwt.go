@@ -8,64 +8,143 @@ import (
 )
 
 type Claims struct {
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Email     string `json:"email"`
-	Role      string `json:"role"`
-	UserId    string `json:"user_id"`
+	FirstName   string   `json:"first_name"`
+	LastName    string   `json:"last_name"`
+	Email       string   `json:"email"`
+	Role        string   `json:"role"`
+	UserId      string   `json:"user_id"`
+	Permissions []string `json:"permissions"`
+	TokenType   string   `json:"token_type"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(cfg *config.Config, firstname, lastname, email, role, userId string) (accessToken, RefreshToken string, err error) {
+// TokenTypeAccess marks bearer tokens. Refresh tokens are opaque strings, so
+// anything else presented as a bearer token is rejected. TokenTypeAPIKey
+// marks claims resolved from an API key; they are never signed.
+const (
+	TokenTypeAccess = "access"
+	TokenTypeAPIKey = "api_key"
+)
+
+type TokenPair struct {
+	AccessToken     string
+	AccessTokenId   string
+	AccessExpiresAt time.Time
+	RefreshToken    string
+}
+
+func GenerateToken(cfg *config.Config, keys *KeySet, firstname, lastname, email, role, userId string, permissions []string) (*TokenPair, error) {
+	// A unique jti keeps two tokens issued in the same second distinct and lets
+	// an access token be revoked before it expires.
+	accessId, err := GenerateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	accessExpiresAt := now.Add(cfg.JWT.ExpiresIn)
+
 	accessClaims := &Claims{
-		FirstName: firstname,
-		LastName:  lastname,
-		Email:     email,
-		Role:      role,
-		UserId:    userId,
+		FirstName:   firstname,
+		LastName:    lastname,
+		Email:       email,
+		Role:        role,
+		UserId:      userId,
+		Permissions: permissions,
+		TokenType:   TokenTypeAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.JWT.ExpiresIn)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        accessId,
+			Issuer:    cfg.JWT.Issuer,
+			Audience:  jwt.ClaimStrings{cfg.JWT.Audience},
+			ExpiresAt: jwt.NewNumericDate(accessExpiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	at := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessToken, err = at.SignedString([]byte(cfg.JWT.Secret))
+	accessToken, err := keys.sign(accessClaims)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
-	refreshClaims := &Claims{
-		FirstName: firstname,
-		LastName:  lastname,
-		Email:     email,
-		Role:      role,
+	// The refresh token is only ever looked up by its hash, so it carries no
+	// claims at all.
+	refreshToken, err := GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:     accessToken,
+		AccessTokenId:   accessId,
+		AccessExpiresAt: accessExpiresAt,
+		RefreshToken:    refreshToken,
+	}, nil
+}
+
+const (
+	PurposeEmailVerification = "email_verification"
+	PurposeMFAChallenge      = "mfa_challenge"
+)
+
+// ScopedClaims back single-purpose tokens such as email verification links
+// and MFA challenges. They are signed with the access token keys but carry the
+// purpose as both token type and audience, so a scoped token can never be
+// replayed as a bearer token or for another purpose.
+type ScopedClaims struct {
+	UserId    string `json:"uid"`
+	Email     string `json:"eml"`
+	TokenType string `json:"token_type"`
+	jwt.RegisteredClaims
+}
+
+func GenerateScopedToken(cfg *config.Config, keys *KeySet, purpose, userId, email string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := &ScopedClaims{
 		UserId:    userId,
+		Email:     email,
+		TokenType: purpose,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.JWT.RefreshTokenExpires)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    cfg.JWT.Issuer,
+			Audience:  jwt.ClaimStrings{purpose},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	rt := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshToken, err := rt.SignedString([]byte(cfg.JWT.Secret))
+	return keys.sign(claims)
+}
+
+func ValidateScopedToken(cfg *config.Config, keys *KeySet, purpose, tokenString string) (*ScopedClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &ScopedClaims{}, keys.keyFunc,
+		jwt.WithValidMethods(keys.algorithms()),
+		jwt.WithIssuer(cfg.JWT.Issuer),
+		jwt.WithAudience(purpose),
+		jwt.WithExpirationRequired(),
+	)
+
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
-	return accessToken, refreshToken, nil
+	if claims, ok := token.Claims.(*ScopedClaims); ok && token.Valid && claims.TokenType == purpose {
+		return claims, nil
+	}
+	return nil, errors.New("invalid token")
 }
 
-func ValidateToken(tokenString, secret string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (any, error) {
-		return []byte(secret), nil
-	})
+func ValidateAccessToken(cfg *config.Config, keys *KeySet, tokenString string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keys.keyFunc,
+		jwt.WithValidMethods(keys.algorithms()),
+		jwt.WithIssuer(cfg.JWT.Issuer),
+		jwt.WithAudience(cfg.JWT.Audience),
+		jwt.WithExpirationRequired(),
+	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && claims.TokenType == TokenTypeAccess {
 		return claims, nil
 	}
 	return nil, errors.New("invalid token")
@@ -0,0 +1,305 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	hmacKeyId     = "hs256"
+	kidTimeLayout = "20060102T150405Z"
+)
+
+var ErrUnknownKeyId = errors.New("unknown key id")
+
+type verificationKey struct {
+	algorithm string
+	key       any
+}
+
+// KeySet holds the key access tokens are signed with and every key they may
+// be verified with. Keeping retired keys in the verification set lets tokens
+// signed before a rotation stay valid until they expire.
+type KeySet struct {
+	signingKid    string
+	signingMethod jwt.SigningMethod
+	signingKey    any
+	verification  map[string]verificationKey
+}
+
+func (k *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.signingMethod, claims)
+	token.Header["kid"] = k.signingKid
+	return token.SignedString(k.signingKey)
+}
+
+func (k *KeySet) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.verification[kid]
+	if !ok {
+		return nil, ErrUnknownKeyId
+	}
+
+	// The algorithm is pinned per key so a token can't pick a weaker one.
+	if token.Method.Alg() != key.algorithm {
+		return nil, fmt.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), kid)
+	}
+	return key.key, nil
+}
+
+func (k *KeySet) algorithms() []string {
+	algorithms := make([]string, 0, len(k.verification))
+	for _, key := range k.verification {
+		if !slices.Contains(algorithms, key.algorithm) {
+			algorithms = append(algorithms, key.algorithm)
+		}
+	}
+	return algorithms
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS publishes the public verification keys. Symmetric keys are never
+// included.
+func (k *KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for kid, key := range k.verification {
+		switch public := key.key.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				Alg: AlgorithmRS256,
+				N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "OKP",
+				Kid: kid,
+				Use: "sig",
+				Alg: AlgorithmEdDSA,
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+
+	slices.SortFunc(jwks.Keys, func(a, b JWK) int { return strings.Compare(a.Kid, b.Kid) })
+	return jwks
+}
+
+// LoadKeySet builds the key set for cfg.JWT.Algorithm. HS256 uses
+// cfg.JWT.Secret; RS256 and EdDSA read every *.pem file in cfg.JWT.KeysDir,
+// where the file name is the kid. The newest private key of the configured
+// algorithm signs unless cfg.JWT.SigningKeyId names another one.
+func LoadKeySet(cfg *config.Config) (*KeySet, error) {
+	keys := &KeySet{verification: make(map[string]verificationKey)}
+
+	if cfg.JWT.Algorithm == "" || cfg.JWT.Algorithm == AlgorithmHS256 {
+		if cfg.JWT.Secret == "" {
+			return nil, errors.New("jwt secret is required for HS256")
+		}
+		keys.signingKid = hmacKeyId
+		keys.signingMethod = jwt.SigningMethodHS256
+		keys.signingKey = []byte(cfg.JWT.Secret)
+		keys.verification[hmacKeyId] = verificationKey{algorithm: AlgorithmHS256, key: []byte(cfg.JWT.Secret)}
+		return keys, nil
+	}
+
+	if cfg.JWT.Algorithm != AlgorithmRS256 && cfg.JWT.Algorithm != AlgorithmEdDSA {
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", cfg.JWT.Algorithm)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(cfg.JWT.KeysDir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(paths)
+
+	signers := make(map[string]crypto.Signer)
+	for _, path := range paths {
+		kid := strings.TrimSuffix(filepath.Base(path), ".pem")
+
+		signer, public, err := readPEMKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", kid, err)
+		}
+
+		algorithm, err := keyAlgorithm(public)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", kid, err)
+		}
+
+		keys.verification[kid] = verificationKey{algorithm: algorithm, key: public}
+		if signer != nil && algorithm == cfg.JWT.Algorithm {
+			signers[kid] = signer
+			keys.signingKid = kid
+		}
+	}
+
+	if cfg.JWT.SigningKeyId != "" {
+		keys.signingKid = cfg.JWT.SigningKeyId
+	}
+
+	signer, ok := signers[keys.signingKid]
+	if !ok {
+		return nil, fmt.Errorf("no %s private key found in %q", cfg.JWT.Algorithm, cfg.JWT.KeysDir)
+	}
+
+	keys.signingKey = signer
+	if cfg.JWT.Algorithm == AlgorithmRS256 {
+		keys.signingMethod = jwt.SigningMethodRS256
+	} else {
+		keys.signingMethod = jwt.SigningMethodEdDSA
+	}
+
+	return keys, nil
+}
+
+// GenerateKey writes a new private key for algorithm to dir and returns its
+// kid. Kids are UTC timestamps so the newest key sorts last.
+func GenerateKey(dir, algorithm string) (string, error) {
+	var signer crypto.Signer
+	var err error
+
+	switch algorithm {
+	case AlgorithmRS256:
+		signer, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgorithmEdDSA:
+		_, signer, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return "", fmt.Errorf("unsupported key algorithm %q", algorithm)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	kid := time.Now().UTC().Format(kidTimeLayout)
+	path := filepath.Join(dir, kid+".pem")
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if err := pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		return "", err
+	}
+
+	return kid, nil
+}
+
+// readPEMKey accepts a PKCS#8 private key or a PKIX public key; the latter
+// can only verify.
+func readPEMKey(path string) (crypto.Signer, crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, nil, errors.New("unsupported private key type")
+		}
+		return signer, signer.Public(), nil
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, key, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+func keyAlgorithm(public crypto.PublicKey) (string, error) {
+	switch public.(type) {
+	case *rsa.PublicKey:
+		return AlgorithmRS256, nil
+	case ed25519.PublicKey:
+		return AlgorithmEdDSA, nil
+	default:
+		return "", errors.New("unsupported public key type")
+	}
+}
+
+// PruneKeys removes keys that were superseded by a newer key before cutoff;
+// no token signed with them can still be valid. It returns the removed kids.
+func PruneKeys(dir string, cutoff time.Time) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(paths)
+
+	var removed []string
+	for i := 0; i < len(paths)-1; i++ {
+		next := strings.TrimSuffix(filepath.Base(paths[i+1]), ".pem")
+		supersededAt, err := time.Parse(kidTimeLayout, next)
+		if err != nil || !supersededAt.Before(cutoff) {
+			continue
+		}
+
+		if err := os.Remove(paths[i]); err != nil {
+			return removed, err
+		}
+		removed = append(removed, strings.TrimSuffix(filepath.Base(paths[i]), ".pem"))
+	}
+	return removed, nil
+}
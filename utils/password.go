@@ -1,12 +1,178 @@
 package utils
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+)
 
-func HashPassword(password string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return string(hash), err
+const (
+	PasswordAlgorithmBcrypt   = "bcrypt"
+	PasswordAlgorithmArgon2id = "argon2id"
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var (
+	ErrPasswordTooLong      = errors.New("password is too long for the configured hasher")
+	ErrUnsupportedHash      = errors.New("unsupported password hash format")
+	ErrUnsupportedAlgorithm = errors.New("unsupported password algorithm")
+)
+
+// PasswordHasher hashes and verifies passwords. Hashes are self-describing
+// (bcrypt modular crypt or PHC-encoded argon2id) so stored hashes created with
+// other algorithms or parameters can still be verified and flagged for rehash.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(encoded, password string) (bool, error)
+	NeedsRehash(encoded string) bool
+}
+
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	saltLen int
+	keyLen  int
+}
+
+type passwordHasher struct {
+	algorithm  string
+	bcryptCost int
+	argon2     argon2Params
 }
 
-func CheckPassword(hash, password string) bool {
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+func (p *passwordHasher) Hash(password string) (string, error) {
+	switch p.algorithm {
+	case PasswordAlgorithmBcrypt:
+		if len(password) > 72 {
+			return "", ErrPasswordTooLong
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), p.bcryptCost)
+		return string(hash), err
+	default:
+		salt := make([]byte, p.argon2.saltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		key := argon2.IDKey([]byte(password), salt, p.argon2.time, p.argon2.memory, p.argon2.threads, uint32(p.argon2.keyLen))
+		return encodeArgon2(p.argon2, salt, key), nil
+	}
+}
+
+func (p *passwordHasher) Verify(encoded, password string) (bool, error) {
+	switch {
+	case encoded == "":
+		// Accounts created through an OIDC provider have no password.
+		return false, nil
+	case isBcryptHash(encoded):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) || errors.Is(err, bcrypt.ErrPasswordTooLong) {
+			return false, nil
+		}
+		return err == nil, err
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		params, salt, key, err := decodeArgon2(encoded)
+		if err != nil {
+			return false, err
+		}
+		other := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, other) == 1, nil
+	default:
+		return false, ErrUnsupportedHash
+	}
+}
+
+func (p *passwordHasher) NeedsRehash(encoded string) bool {
+	switch p.algorithm {
+	case PasswordAlgorithmBcrypt:
+		if !isBcryptHash(encoded) {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(encoded))
+		return err != nil || cost != p.bcryptCost
+	default:
+		params, _, _, err := decodeArgon2(encoded)
+		return err != nil || params != p.argon2
+	}
+}
+
+func isBcryptHash(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+func encodeArgon2(params argon2Params, salt, key []byte) string {
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.memory, params.time, params.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+func decodeArgon2(encoded string) (argon2Params, []byte, []byte, error) {
+	var params argon2Params
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != PasswordAlgorithmArgon2id {
+		return params, nil, nil, ErrUnsupportedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrUnsupportedHash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return params, nil, nil, ErrUnsupportedHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrUnsupportedHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrUnsupportedHash
+	}
+
+	params.saltLen = len(salt)
+	params.keyLen = len(key)
+	return params, salt, key, nil
+}
+
+func NewPasswordHasher(cfg *config.Config) (PasswordHasher, error) {
+	hasher := &passwordHasher{
+		algorithm:  cfg.Password.Algorithm,
+		bcryptCost: cfg.Password.BcryptCost,
+		argon2: argon2Params{
+			memory:  cfg.Password.Argon2Memory,
+			time:    cfg.Password.Argon2Time,
+			threads: cfg.Password.Argon2Threads,
+			saltLen: argon2SaltLength,
+			keyLen:  argon2KeyLength,
+		},
+	}
+
+	switch hasher.algorithm {
+	case PasswordAlgorithmBcrypt:
+		if hasher.bcryptCost < bcrypt.MinCost || hasher.bcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case PasswordAlgorithmArgon2id:
+		if hasher.argon2.memory == 0 || hasher.argon2.time == 0 || hasher.argon2.threads == 0 {
+			return nil, errors.New("argon2id memory, time and threads must be greater than zero")
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, hasher.algorithm)
+	}
+
+	return hasher, nil
 }
@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"errors"
+	"github.com/saleh-ghazimoradi/Projectopher/config"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+)
+
+func newTestHasher(t *testing.T, password config.Password) PasswordHasher {
+	t.Helper()
+	hasher, err := NewPasswordHasher(&config.Config{Password: password})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hasher
+}
+
+func bcryptConfig(cost int) config.Password {
+	return config.Password{Algorithm: PasswordAlgorithmBcrypt, BcryptCost: cost}
+}
+
+func argon2Config(memory, time uint32, threads uint8) config.Password {
+	return config.Password{Algorithm: PasswordAlgorithmArgon2id, Argon2Memory: memory, Argon2Time: time, Argon2Threads: threads}
+}
+
+func TestPasswordHasherHashAndVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		config config.Password
+		prefix string
+	}{
+		{name: "bcrypt", config: bcryptConfig(bcrypt.MinCost), prefix: "$2a$"},
+		{name: "argon2id", config: argon2Config(1024, 1, 1), prefix: "$argon2id$v=19$m=1024,t=1,p=1$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasher := newTestHasher(t, tt.config)
+
+			encoded, err := hasher.Hash("correct horse battery staple")
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			if !strings.HasPrefix(encoded, tt.prefix) {
+				t.Fatalf("Hash = %q, want prefix %q", encoded, tt.prefix)
+			}
+
+			again, err := hasher.Hash("correct horse battery staple")
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			if again == encoded {
+				t.Fatal("Hash returned the same encoding twice, want a fresh salt")
+			}
+
+			if ok, err := hasher.Verify(encoded, "correct horse battery staple"); err != nil || !ok {
+				t.Fatalf("Verify(correct) = %v, %v, want true", ok, err)
+			}
+			if ok, err := hasher.Verify(encoded, "wrong password"); err != nil || ok {
+				t.Fatalf("Verify(wrong) = %v, %v, want false", ok, err)
+			}
+			if hasher.NeedsRehash(encoded) {
+				t.Fatal("NeedsRehash = true for a hash made with the current settings")
+			}
+		})
+	}
+}
+
+func TestPasswordHasherVerifiesOtherAlgorithms(t *testing.T) {
+	bcryptHash, err := newTestHasher(t, bcryptConfig(bcrypt.MinCost)).Hash("secret-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	argon2Hash, err := newTestHasher(t, argon2Config(1024, 1, 1)).Hash("secret-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Existing hashes keep working after the configured algorithm changes.
+	for name, hasher := range map[string]PasswordHasher{
+		"bcrypt hasher":   newTestHasher(t, bcryptConfig(bcrypt.MinCost+1)),
+		"argon2id hasher": newTestHasher(t, argon2Config(2048, 2, 2)),
+	} {
+		for hashName, encoded := range map[string]string{"bcrypt": bcryptHash, "argon2id": argon2Hash} {
+			if ok, err := hasher.Verify(encoded, "secret-password"); err != nil || !ok {
+				t.Errorf("%s: Verify(%s hash) = %v, %v, want true", name, hashName, ok, err)
+			}
+			if !hasher.NeedsRehash(encoded) {
+				t.Errorf("%s: NeedsRehash(%s hash) = false, want true", name, hashName)
+			}
+		}
+	}
+}
+
+func TestPasswordHasherNeedsRehash(t *testing.T) {
+	base := newTestHasher(t, argon2Config(1024, 1, 1))
+	encoded, err := base.Hash("secret-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcryptHash, err := newTestHasher(t, bcryptConfig(bcrypt.MinCost)).Hash("secret-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		config  config.Password
+		encoded string
+		want    bool
+	}{
+		{name: "same argon2id params", config: argon2Config(1024, 1, 1), encoded: encoded, want: false},
+		{name: "argon2id memory changed", config: argon2Config(2048, 1, 1), encoded: encoded, want: true},
+		{name: "argon2id time changed", config: argon2Config(1024, 2, 1), encoded: encoded, want: true},
+		{name: "argon2id threads changed", config: argon2Config(1024, 1, 2), encoded: encoded, want: true},
+		{name: "bcrypt to argon2id", config: argon2Config(1024, 1, 1), encoded: bcryptHash, want: true},
+		{name: "argon2id to bcrypt", config: bcryptConfig(bcrypt.MinCost), encoded: encoded, want: true},
+		{name: "same bcrypt cost", config: bcryptConfig(bcrypt.MinCost), encoded: bcryptHash, want: false},
+		{name: "bcrypt cost changed", config: bcryptConfig(bcrypt.MinCost + 1), encoded: bcryptHash, want: true},
+		{name: "malformed hash", config: argon2Config(1024, 1, 1), encoded: "$argon2id$garbage", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newTestHasher(t, tt.config).NeedsRehash(tt.encoded); got != tt.want {
+				t.Fatalf("NeedsRehash = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasswordHasherVerifyRejectsBadHashes(t *testing.T) {
+	hasher := newTestHasher(t, argon2Config(1024, 1, 1))
+
+	tests := []struct {
+		name    string
+		encoded string
+		wantErr error
+	}{
+		{name: "empty hash", encoded: ""},
+		{name: "unknown scheme", encoded: "$1$salt$hash", wantErr: ErrUnsupportedHash},
+		{name: "plain text", encoded: "secret-password", wantErr: ErrUnsupportedHash},
+		{name: "wrong argon2 version", encoded: "$argon2id$v=16$m=1024,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5", wantErr: ErrUnsupportedHash},
+		{name: "bad argon2 params", encoded: "$argon2id$v=19$m=x,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5", wantErr: ErrUnsupportedHash},
+		{name: "bad argon2 salt", encoded: "$argon2id$v=19$m=1024,t=1,p=1$!!!$a2V5a2V5", wantErr: ErrUnsupportedHash},
+		{name: "empty argon2 key", encoded: "$argon2id$v=19$m=1024,t=1,p=1$c2FsdHNhbHQ$", wantErr: ErrUnsupportedHash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := hasher.Verify(tt.encoded, "secret-password")
+			if ok {
+				t.Fatal("Verify = true, want false")
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBcryptRejectsLongPasswords(t *testing.T) {
+	hasher := newTestHasher(t, bcryptConfig(bcrypt.MinCost))
+	if _, err := hasher.Hash(strings.Repeat("a", 73)); !errors.Is(err, ErrPasswordTooLong) {
+		t.Fatalf("Hash error = %v, want %v", err, ErrPasswordTooLong)
+	}
+}
+
+func TestNewPasswordHasherValidatesConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  config.Password
+		wantErr error
+	}{
+		{name: "unknown algorithm", config: config.Password{Algorithm: "md5"}, wantErr: ErrUnsupportedAlgorithm},
+		{name: "bcrypt cost too low", config: bcryptConfig(bcrypt.MinCost - 1)},
+		{name: "bcrypt cost too high", config: bcryptConfig(bcrypt.MaxCost + 1)},
+		{name: "argon2id without memory", config: argon2Config(0, 1, 1)},
+		{name: "argon2id without threads", config: argon2Config(1024, 1, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPasswordHasher(&config.Config{Password: tt.config})
+			if err == nil {
+				t.Fatal("NewPasswordHasher succeeded, want an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewPasswordHasher error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
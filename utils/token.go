@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+func GenerateRandomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+const APIKeyPrefix = "pk_"
+
+// GenerateAPIKey returns a key of the form pk_<prefix>_<secret> along with its
+// prefix. The prefix is not secret and identifies the key in listings.
+func GenerateAPIKey() (key, prefix string, err error) {
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+
+	secret, err := GenerateRandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	prefix = APIKeyPrefix + hex.EncodeToString(id)
+	return prefix + "_" + secret, prefix, nil
+}
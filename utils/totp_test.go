@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA1 seed from RFC 6238 appendix B, "12345678901234567890".
+var rfc6238Secret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// The RFC lists 8 digit codes; these are their last 6 digits.
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{unix: 59, code: "287082"},
+	{unix: 1111111109, code: "081804"},
+	{unix: 1111111111, code: "050471"},
+	{unix: 1234567890, code: "005924"},
+	{unix: 2000000000, code: "279037"},
+	{unix: 20000000000, code: "353130"},
+}
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	for _, tt := range rfc6238Vectors {
+		t.Run(time.Unix(tt.unix, 0).UTC().Format(time.RFC3339), func(t *testing.T) {
+			got, err := TOTPCode(rfc6238Secret, TOTPStep(time.Unix(tt.unix, 0)))
+			if err != nil {
+				t.Fatalf("TOTPCode: %v", err)
+			}
+			if got != tt.code {
+				t.Fatalf("TOTPCode = %q, want %q", got, tt.code)
+			}
+		})
+	}
+}
+
+func TestTOTPCodeAcceptsLowercaseSecret(t *testing.T) {
+	got, err := TOTPCode(strings.ToLower(rfc6238Secret), TOTPStep(time.Unix(59, 0)))
+	if err != nil {
+		t.Fatalf("TOTPCode: %v", err)
+	}
+	if got != "287082" {
+		t.Fatalf("TOTPCode = %q, want 287082", got)
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	// 1111111111 is step 37037037, and 1111111109 is the step before it.
+	at := time.Unix(1111111111, 0)
+	current := TOTPStep(at)
+
+	code := func(step int64) string {
+		c, err := TOTPCode(rfc6238Secret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		skew     int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current step", code: "050471", skew: 0, wantStep: current, wantOK: true},
+		{name: "previous step without skew", code: "081804", skew: 0},
+		{name: "previous step within skew", code: "081804", skew: 1, wantStep: current - 1, wantOK: true},
+		{name: "next step within skew", code: code(current + 1), skew: 1, wantStep: current + 1, wantOK: true},
+		{name: "two steps back with skew of one", code: code(current - 2), skew: 1},
+		{name: "two steps ahead with skew of two", code: code(current + 2), skew: 2, wantStep: current + 2, wantOK: true},
+		{name: "wrong code", code: "000000", skew: 1},
+		{name: "too short", code: "50471", skew: 1},
+		{name: "eight digit code", code: "14050471", skew: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(rfc6238Secret, tt.code, at, tt.skew)
+			if ok != tt.wantOK {
+				t.Fatalf("ValidateTOTP ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && step != tt.wantStep {
+				t.Fatalf("ValidateTOTP step = %d, want %d", step, tt.wantStep)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPRejectsInvalidSecret(t *testing.T) {
+	if _, ok := ValidateTOTP("not base32!", "287082", time.Unix(59, 0), 1); ok {
+		t.Fatal("ValidateTOTP accepted a code for an invalid secret")
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret %q is not unpadded base32: %v", secret, err)
+	}
+	if len(key) != 20 {
+		t.Fatalf("secret is %d bytes, want 20", len(key))
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri, err := url.Parse(TOTPURI("Projectopher", "jane@example.com", rfc6238Secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if uri.Scheme != "otpauth" || uri.Host != "totp" {
+		t.Fatalf("URI = %s, want otpauth://totp/...", uri)
+	}
+	if uri.Path != "/Projectopher:jane@example.com" {
+		t.Fatalf("label = %q, want /Projectopher:jane@example.com", uri.Path)
+	}
+
+	query := uri.Query()
+	for key, want := range map[string]string{
+		"secret":    rfc6238Secret,
+		"issuer":    "Projectopher",
+		"algorithm": "SHA1",
+		"digits":    "6",
+		"period":    "30",
+	} {
+		if got := query.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != 10 {
+		t.Fatalf("got %d codes, want 10", len(codes))
+	}
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("code %q is not formatted as xxxxx-xxxxx", code)
+		}
+		if seen[code] {
+			t.Errorf("code %q generated twice", code)
+		}
+		seen[code] = true
+
+		variant := strings.ToUpper(strings.Replace(code, "-", " ", 1))
+		if HashRecoveryCode(variant) != HashRecoveryCode(code) {
+			t.Errorf("HashRecoveryCode(%q) differs from HashRecoveryCode(%q)", variant, code)
+		}
+	}
+}